@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/seed"
+	"github.com/rochaeduardo997/irede_golang_dev/scripts"
+	"github.com/spf13/cobra"
+)
+
+func (a *app) migrateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending database migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connectDB(); err != nil {
+				return err
+			}
+			migrations, err := fs.Sub(scripts.Migrations, "migrations")
+			if err != nil {
+				return err
+			}
+			applied, err := database.Migrate(cmd.Context(), a.db, migrations)
+			rows := [][]any{}
+			for _, version := range applied {
+				rows = append(rows, []any{version})
+			}
+			if printErr := a.print(map[string]any{"applied": applied}, []string{"APPLIED"}, rows); err == nil {
+				err = printErr
+			}
+			return err
+		},
+	}
+}
+
+func (a *app) seedCommand() *cobra.Command {
+	var generate, printFile bool
+	generator := uint64(seed.DefaultSeed)
+	volume := seed.DefaultVolume
+	cmd := &cobra.Command{
+		Use:   "seed [FILE]",
+		Short: "Create the cinemas, movies and rooms described in a JSON seed file or generated from --seed",
+		Long:  "Seeding twice is harmless: cinemas, movies and rooms that already exist are reused. --generate builds the data from a fixed seed, so the same flags always produce the same catalog.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if generate == (len(args) == 1) {
+				return errors.New("pass either a seed file or --generate")
+			}
+			var f *seed.File
+			var err error
+			if generate {
+				f, err = seed.NewGenerator(generator).File(volume)
+			} else {
+				f, err = loadSeed(args[0])
+			}
+			if err != nil {
+				return err
+			}
+			if printFile {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(f)
+			}
+			if err = a.connectDB(); err != nil {
+				return err
+			}
+			seeder := &seed.Seeder{Cinemas: a.cinemas, Movies: a.movies, Rooms: a.rooms}
+			result, err := seeder.Apply(cmd.Context(), f)
+			if err != nil {
+				return err
+			}
+			return a.print(result, []string{"CINEMAS", "MOVIES", "ROOMS"}, [][]any{{result.Cinemas, result.Movies, result.Rooms}})
+		},
+	}
+	cmd.Flags().BoolVar(&generate, "generate", false, "generate the data instead of reading a file")
+	cmd.Flags().BoolVar(&printFile, "print", false, "print the seed file instead of applying it")
+	cmd.Flags().Uint64Var(&generator, "seed", seed.DefaultSeed, "generator seed")
+	cmd.Flags().IntVar(&volume.Cinemas, "cinemas", volume.Cinemas, "cinemas to generate")
+	cmd.Flags().IntVar(&volume.Movies, "movies", volume.Movies, "movies to generate")
+	cmd.Flags().IntVar(&volume.RoomsPerCinema, "rooms-per-cinema", volume.RoomsPerCinema, "rooms to generate in each cinema")
+	cmd.Flags().IntVar(&volume.MoviesPerRoom, "movies-per-room", volume.MoviesPerRoom, "movies shown in each generated room")
+	return cmd
+}
+
+func loadSeed(file string) (result *seed.File, err error) {
+	input, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	return seed.Load(input)
+}
+
+func formatFromExtension(file string) string {
+	return strings.TrimPrefix(filepath.Ext(file), ".")
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/client"
+	controller_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/controller/cinema"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
+	controller_room "github.com/rochaeduardo997/irede_golang_dev/internal/controller/room"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/logger"
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	"github.com/spf13/cobra"
+)
+
+// app holds what every command needs; controllers talk to the database directly unless --api points at a running server.
+type app struct {
+	api     string
+	token   string
+	apiKey  string
+	output  string
+	db      *sql.DB
+	cinemas controller_interfaces.IGenericController[model_cinema.Cinema]
+	movies  controller_interfaces.IGenericController[model_movie.Movie]
+	rooms   controller_interfaces.IGenericController[model_room.Room]
+}
+
+func main() {
+	godotenv.Load()
+	a := &app{}
+	root := &cobra.Command{
+		Use:          "cinemactl",
+		Short:        "Manage the movie catalog and rooms",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if a.output != "table" && a.output != "json" {
+				return errors.New("output must be table or json")
+			}
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVar(&a.api, "api", os.Getenv("CINEMACTL_API"), "base URL of a running API; empty uses the database from .env")
+	root.PersistentFlags().StringVar(&a.token, "token", os.Getenv("CINEMACTL_TOKEN"), "bearer token for --api")
+	root.PersistentFlags().StringVar(&a.apiKey, "api-key", os.Getenv("CINEMACTL_API_KEY"), "API key for --api")
+	root.PersistentFlags().StringVarP(&a.output, "output", "o", "table", "table or json")
+	root.AddCommand(a.moviesCommand(), a.roomsCommand(), a.migrateCommand(), a.seedCommand())
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// connect wires the controllers for commands that work against either backend.
+func (a *app) connect() error {
+	if a.api != "" {
+		c := client.NewClient(&client.Client{BaseURL: a.api, Token: a.token, APIKey: a.apiKey})
+		a.movies, a.rooms = c.Movies(), c.Rooms()
+		return nil
+	}
+	return a.connectDB()
+}
+
+// connectDB is required by commands that only make sense next to the database, such as migrate and seed.
+func (a *app) connectDB() (err error) {
+	if a.api != "" {
+		return errors.New("this command needs a database connection; drop --api")
+	}
+	l := logger.NewLogger(os.Stderr)
+	slog.SetDefault(l)
+	a.db, err = database.NewDatabaseConnection()
+	if err != nil {
+		return err
+	}
+	a.cinemas, _ = controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: a.db, Logger: l})
+	a.movies, _ = controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: a.db, Logger: l})
+	a.rooms, _ = controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: a.db, MovieController: a.movies, Logger: l})
+	return nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/importer"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	"github.com/spf13/cobra"
+)
+
+func (a *app) moviesCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "movies", Short: "List, create, update, delete and import movies"}
+
+	page := uint16(1)
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List one page of movies",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connect(); err != nil {
+				return err
+			}
+			result, err := a.movies.FindAll(cmd.Context(), page)
+			if err != nil {
+				return err
+			}
+			return a.printMovies(result, result.Registers...)
+		},
+	}
+	list.Flags().Uint16Var(&page, "page", 1, "page to list")
+
+	get := &cobra.Command{
+		Use:   "get ID",
+		Short: "Show a movie",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connect(); err != nil {
+				return err
+			}
+			movie, err := a.movies.FindBy(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return a.printMovies(movie, movie)
+		},
+	}
+
+	movie := &model_movie.Movie{}
+	var duration string
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a movie",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			movie.DurationInSeconds, err = model_movie.ParseDuration(duration)
+			if err == nil {
+				err = movie.IsValid()
+			}
+			if err == nil {
+				err = a.connect()
+			}
+			if err != nil {
+				return err
+			}
+			id, err := a.movies.Create(cmd.Context(), movie)
+			if err != nil {
+				return err
+			}
+			return a.printResult("id", id)
+		},
+	}
+	movieFlags(create, movie, &duration)
+
+	changes := &model_movie.Movie{}
+	var newDuration string
+	update := &cobra.Command{
+		Use:   "update ID",
+		Short: "Change the given fields of a movie",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connect(); err != nil {
+				return err
+			}
+			movie, err := a.movies.FindBy(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			flags := cmd.Flags()
+			if flags.Changed("name") {
+				movie.Name = changes.Name
+			}
+			if flags.Changed("director") {
+				movie.Director = changes.Director
+			}
+			if flags.Changed("year") {
+				movie.Year = changes.Year
+			}
+			if flags.Changed("duration") {
+				if movie.DurationInSeconds, err = model_movie.ParseDuration(newDuration); err != nil {
+					return err
+				}
+			}
+			if err = movie.IsValid(); err != nil {
+				return err
+			}
+			result, err := a.movies.UpdateBy(cmd.Context(), args[0], movie)
+			if err != nil {
+				return err
+			}
+			return a.printResult("updated", result)
+		},
+	}
+	movieFlags(update, changes, &newDuration)
+
+	remove := &cobra.Command{
+		Use:   "delete ID",
+		Short: "Delete a movie",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connect(); err != nil {
+				return err
+			}
+			result, err := a.movies.DeleteBy(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return a.printResult("deleted", result)
+		},
+	}
+
+	opts := &importer.Options{}
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import a CSV or NDJSON catalog, - reads stdin",
+		Long:  "CSV needs a header with name, director and duration (seconds or HH:MM:SS); year is optional. NDJSON takes one movie object per line.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			format := opts.Format
+			if format == "" {
+				format = formatFromExtension(args[0])
+			}
+			if opts.Format, err = importer.ParseFormat(format); err != nil {
+				return err
+			}
+			input := os.Stdin
+			if args[0] != "-" {
+				if input, err = os.Open(args[0]); err != nil {
+					return err
+				}
+				defer input.Close()
+			}
+			if err = a.connectDB(); err != nil {
+				return err
+			}
+			mi := &importer.MovieImporter{Db: a.db, Controller: a.movies}
+			report, err := mi.Import(cmd.Context(), input, opts)
+			if err != nil {
+				return err
+			}
+			rows := [][]any{}
+			for _, row := range report.Rows {
+				rows = append(rows, []any{row.Line, row.Action, row.Id, row.Error})
+			}
+			err = a.print(report, []string{"LINE", "ACTION", "ID", "ERROR"}, rows)
+			if err == nil && report.Failed > 0 {
+				err = errRowsFailed(report.Failed)
+			}
+			return err
+		},
+	}
+	importCmd.Flags().StringVar(&opts.Format, "format", "", "csv or ndjson, defaults to the file extension")
+	importCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "validate and report without saving")
+	importCmd.Flags().BoolVar(&opts.Upsert, "upsert", false, "update movies with the same name, director and year")
+
+	cmd.AddCommand(list, get, create, update, remove, importCmd)
+	return cmd
+}
+
+func movieFlags(cmd *cobra.Command, movie *model_movie.Movie, duration *string) {
+	cmd.Flags().StringVar(&movie.Name, "name", "", "movie name")
+	cmd.Flags().StringVar(&movie.Director, "director", "", "movie director")
+	cmd.Flags().Uint16Var(&movie.Year, "year", 0, "release year")
+	cmd.Flags().StringVar(duration, "duration", "", "duration in seconds or HH:MM:SS")
+}
+
+type errRowsFailed int
+
+func (e errRowsFailed) Error() string {
+	return strconv.Itoa(int(e)) + " rows failed"
+}
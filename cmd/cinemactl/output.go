@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+)
+
+// print writes value as indented JSON, or as a table of header and rows.
+func (a *app) print(value any, header []string, rows [][]any) error {
+	if a.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = fmt.Sprint(cell)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
+
+func (a *app) printMovies(value any, movies ...*model_movie.Movie) error {
+	rows := [][]any{}
+	for _, m := range movies {
+		rows = append(rows, []any{m.Id, m.Name, m.Director, m.Year, m.DurationInHours()})
+	}
+	return a.print(value, []string{"ID", "NAME", "DIRECTOR", "YEAR", "DURATION"}, rows)
+}
+
+func (a *app) printRooms(value any, rooms ...*model_room.Room) error {
+	rows := [][]any{}
+	for _, r := range rooms {
+		names := []string{}
+		for _, m := range r.Movies {
+			names = append(names, m.Name)
+		}
+		rows = append(rows, []any{r.Id, r.CinemaId, r.Number, r.Description, strings.Join(names, ", ")})
+	}
+	return a.print(value, []string{"ID", "CINEMA", "NUMBER", "DESCRIPTION", "MOVIES"}, rows)
+}
+
+func (a *app) printResult(field string, value any) error {
+	return a.print(map[string]any{field: value}, []string{strings.ToUpper(field)}, [][]any{{value}})
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"slices"
+
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	"github.com/spf13/cobra"
+)
+
+func (a *app) roomsCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "rooms", Short: "List, create, update and delete rooms and their movies"}
+
+	page := uint16(1)
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List one page of rooms",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connect(); err != nil {
+				return err
+			}
+			result, err := a.rooms.FindAll(cmd.Context(), page)
+			if err != nil {
+				return err
+			}
+			return a.printRooms(result, result.Registers...)
+		},
+	}
+	list.Flags().Uint16Var(&page, "page", 1, "page to list")
+
+	get := &cobra.Command{
+		Use:   "get ID",
+		Short: "Show a room and its movies",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connect(); err != nil {
+				return err
+			}
+			room, err := a.rooms.FindBy(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return a.printRooms(room, room)
+		},
+	}
+
+	room := &model_room.Room{}
+	var movieIds []string
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a room",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connect(); err != nil {
+				return err
+			}
+			for _, id := range movieIds {
+				movie, err := a.movies.FindBy(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				room.Movies = append(room.Movies, movie)
+			}
+			if err := room.IsValid(); err != nil {
+				return err
+			}
+			id, err := a.rooms.Create(cmd.Context(), room)
+			if err != nil {
+				return err
+			}
+			return a.printResult("id", id)
+		},
+	}
+	create.Flags().StringVar(&room.CinemaId, "cinema", "", "cinema id")
+	create.Flags().Uint16Var(&room.Number, "number", 0, "room number, unique within the cinema")
+	create.Flags().StringVar(&room.Description, "description", "", "room description")
+	create.Flags().StringSliceVar(&movieIds, "movie", nil, "movie id to show in the room, repeatable")
+
+	changes := &model_room.Room{}
+	update := &cobra.Command{
+		Use:   "update ID",
+		Short: "Change the number or description of a room",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.changeRoom(cmd, args[0], func(room *model_room.Room) error {
+				if cmd.Flags().Changed("number") {
+					room.Number = changes.Number
+				}
+				if cmd.Flags().Changed("description") {
+					room.Description = changes.Description
+				}
+				return nil
+			})
+		},
+	}
+	update.Flags().Uint16Var(&changes.Number, "number", 0, "room number, unique within the cinema")
+	update.Flags().StringVar(&changes.Description, "description", "", "room description")
+
+	remove := &cobra.Command{
+		Use:   "delete ID",
+		Short: "Delete a room",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.connect(); err != nil {
+				return err
+			}
+			result, err := a.rooms.DeleteBy(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return a.printResult("deleted", result)
+		},
+	}
+
+	attach := &cobra.Command{
+		Use:   "attach ROOM_ID MOVIE_ID...",
+		Short: "Add movies to a room's lineup",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.changeRoom(cmd, args[0], func(room *model_room.Room) error {
+				for _, id := range args[1:] {
+					if slices.ContainsFunc(room.Movies, func(m *model_movie.Movie) bool { return m.Id == id }) {
+						continue
+					}
+					movie, err := a.movies.FindBy(cmd.Context(), id)
+					if err != nil {
+						return err
+					}
+					room.Movies = append(room.Movies, movie)
+				}
+				return nil
+			})
+		},
+	}
+
+	detach := &cobra.Command{
+		Use:   "detach ROOM_ID MOVIE_ID...",
+		Short: "Remove movies from a room's lineup",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.changeRoom(cmd, args[0], func(room *model_room.Room) error {
+				room.Movies = slices.DeleteFunc(room.Movies, func(m *model_movie.Movie) bool { return slices.Contains(args[1:], m.Id) })
+				return nil
+			})
+		},
+	}
+
+	cmd.AddCommand(list, get, create, update, remove, attach, detach)
+	return cmd
+}
+
+// changeRoom loads the room, applies change and saves it back; room updates replace the whole lineup.
+func (a *app) changeRoom(cmd *cobra.Command, id string, change func(room *model_room.Room) error) error {
+	if err := a.connect(); err != nil {
+		return err
+	}
+	room, err := a.rooms.FindBy(cmd.Context(), id)
+	if err != nil {
+		return err
+	}
+	if err = change(room); err != nil {
+		return err
+	}
+	result, err := a.rooms.UpdateBy(cmd.Context(), id, room)
+	if err != nil {
+		return err
+	}
+	return a.printResult("updated", result)
+}
@@ -1,21 +1,50 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"log"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	controller_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/controller/apikey"
+	controller_audit "github.com/rochaeduardo997/irede_golang_dev/internal/controller/audit"
+	controller_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/controller/cinema"
+	controller_export "github.com/rochaeduardo997/irede_golang_dev/internal/controller/export"
 	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
 	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
 	controller_room "github.com/rochaeduardo997/irede_golang_dev/internal/controller/room"
+	controller_user "github.com/rochaeduardo997/irede_golang_dev/internal/controller/user"
 	_ "github.com/rochaeduardo997/irede_golang_dev/internal/docs"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
 	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/logger"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/metrics"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tracing"
+	model_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/model/apikey"
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
 	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
 	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	model_user "github.com/rochaeduardo997/irede_golang_dev/internal/model/user"
+	view_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/view/apikey"
+	view_audit "github.com/rochaeduardo997/irede_golang_dev/internal/view/audit"
+	view_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/view/cinema"
 	view_docs "github.com/rochaeduardo997/irede_golang_dev/internal/view/docs"
+	view_export "github.com/rochaeduardo997/irede_golang_dev/internal/view/export"
+	view_health "github.com/rochaeduardo997/irede_golang_dev/internal/view/health"
+	view_metrics "github.com/rochaeduardo997/irede_golang_dev/internal/view/metrics"
 	view_movie "github.com/rochaeduardo997/irede_golang_dev/internal/view/movie"
 	view_room "github.com/rochaeduardo997/irede_golang_dev/internal/view/room"
+	view_user "github.com/rochaeduardo997/irede_golang_dev/internal/view/user"
 	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // @title           Movies
@@ -29,6 +58,16 @@ import (
 // @host      localhost:3000
 // @BasePath  /api/v1
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 JWT sent as "Bearer <token>"
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key for machine-to-machine integrations
+
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 func main() {
@@ -37,30 +76,204 @@ func main() {
 		log.Fatal("Error loading .env file, err: ", err)
 	}
 
+	l := logger.NewLogger(os.Stdout)
+	slog.SetDefault(l)
+
+	tp, err := tracing.NewTracerProvider(context.Background())
+	if err != nil {
+		log.Fatal("Error creating tracer provider, err: ", err)
+	}
+	defer tp.Shutdown(context.Background())
+
+	authenticator, err := auth.NewAuthenticatorFromEnv()
+	if err != nil {
+		log.Fatal("Error configuring authentication, err: ", err)
+	}
+	policy, err := auth.LoadPolicy(os.Getenv("POLICY_FILE"))
+	if err != nil {
+		log.Fatal("Error loading authorization policy, err: ", err)
+	}
+
 	db := instanceDB()
+	m := metrics.NewMetrics(db)
 
-	cm := instanceControllerMovie(db)
-	cr := instanceControllerRoom(db, cm)
+	cc := instanceControllerCinema(db, l, m, tp)
+	cm := instanceControllerMovie(db, l, m, tp)
+	cr := instanceControllerRoom(db, cm, l, m, tp)
+	cu := instanceControllerUser(db, l)
+	authenticator.Validate = func(ctx context.Context, principal *auth.Principal) error {
+		user, err := cu.FindBy(ctx, principal.Subject)
+		if err == nil && user.Disabled {
+			return errors.New("user disabled")
+		}
+		return nil
+	}
+	ensureAdminUser(cu)
+	ck := instanceControllerAPIKey(db, l)
+	ca := instanceControllerAudit(db, l)
+	authenticator.APIKeys = func(ctx context.Context, key string) (*auth.Principal, error) {
+		return apiKeyPrincipal(ctx, ck, key)
+	}
 
-	httpAdapter, _ := http_adapter.NewGorillaMux()
+	httpAdapter := instanceHTTPAdapter()
+	httpAdapter.Use(
+		tracing.Middleware(tp),
+		http_adapter.RequestID(),
+		m.Middleware(),
+		http_adapter.AccessLog(l),
+		http_adapter.Recovery(l),
+		http_adapter.CORS(http_adapter.CORSOptions{AllowedOrigins: strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",")}),
+		http_adapter.BodyLimit(maxBodyBytes()),
+	)
 	view_docs.NewDocsView(&view_docs.DocsView{HTTPAdapter: httpAdapter})
-	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
-	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
+	view_health.NewHealthView(&view_health.HealthView{HTTPAdapter: httpAdapter, Db: db})
+	view_metrics.NewMetricsView(&view_metrics.MetricsView{HTTPAdapter: httpAdapter, Metrics: m})
+	// clientLimit runs before authentication, so failed credentials are throttled by IP like everything else.
+	clientLimit := rateLimit("IP", nil)
+	apiMiddlewares := func(group string) []http_adapter.Middleware {
+		return []http_adapter.Middleware{clientLimit, authenticator.Middleware(), rateLimit(group, auth.RateLimitKey)}
+	}
+	idempotency := http_adapter.Idempotency(http_adapter.IdempotencyOptions{TTL: idempotencyTTL(), MaxEntries: idempotencyMaxEntries(), Key: auth.RateLimitKey})
+	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, Logger: l, Middlewares: apiMiddlewares("MOVIES"), CreateMiddlewares: []http_adapter.Middleware{idempotency}, Policy: policy, ControllerMovie: cm})
+	view_cinema.NewViewCinema(&view_cinema.ViewCinema{HTTPAdapter: httpAdapter, Logger: l, Middlewares: apiMiddlewares("CINEMAS"), Policy: policy, ControllerCinema: cc})
+	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, Logger: l, Middlewares: apiMiddlewares("ROOMS"), CreateMiddlewares: []http_adapter.Middleware{idempotency}, Policy: policy, ControllerRoom: cr, ControllerMovie: cm})
+	view_user.NewViewUser(&view_user.ViewUser{HTTPAdapter: httpAdapter, Logger: l, Middlewares: apiMiddlewares("USERS"), LoginMiddlewares: []http_adapter.Middleware{clientLimit, rateLimit("LOGIN", nil)}, Policy: policy, Authenticator: authenticator, ControllerUser: cu})
+	view_apikey.NewViewAPIKey(&view_apikey.ViewAPIKey{HTTPAdapter: httpAdapter, Logger: l, Middlewares: apiMiddlewares("APIKEYS"), Policy: policy, ControllerAPIKey: ck})
+	view_export.NewViewExport(&view_export.ViewExport{HTTPAdapter: httpAdapter, Logger: l, Middlewares: apiMiddlewares("EXPORT"), Policy: policy, ControllerExport: instanceControllerExport(db, l)})
+	view_audit.NewViewAudit(&view_audit.ViewAudit{HTTPAdapter: httpAdapter, Logger: l, Middlewares: apiMiddlewares("AUDIT"), Policy: policy, ControllerAudit: ca})
 
 	httpAdapter.Listen()
 }
 
+func maxBodyBytes() (result int64) {
+	result, err := strconv.ParseInt(os.Getenv("API_MAX_BODY_BYTES"), 10, 64)
+	if err != nil || result <= 0 {
+		return 1 << 20
+	}
+	return
+}
+
+func idempotencyTTL() (result time.Duration) {
+	result, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil || result <= 0 {
+		return 24 * time.Hour
+	}
+	return
+}
+
+// idempotencyMaxEntries reads IDEMPOTENCY_MAX_ENTRIES; zero leaves the middleware's default.
+func idempotencyMaxEntries() (result int) {
+	result, _ = strconv.Atoi(os.Getenv("IDEMPOTENCY_MAX_ENTRIES"))
+	return
+}
+
+// rateLimit reads RATE_LIMIT_<GROUP>, falling back to RATE_LIMIT, as "<requests per second>:<burst>"; unset disables it.
+// A nil key limits by client IP.
+func rateLimit(group string, key func(r *http.Request) string) http_adapter.Middleware {
+	value := os.Getenv("RATE_LIMIT_" + group)
+	if value == "" {
+		value = os.Getenv("RATE_LIMIT")
+	}
+	opts := http_adapter.RateLimitOptions{Key: key}
+	rate, burst, _ := strings.Cut(value, ":")
+	opts.Rate, _ = strconv.ParseFloat(rate, 64)
+	opts.Burst, _ = strconv.Atoi(burst)
+	if opts.Burst == 0 {
+		opts.Burst = int(math.Ceil(opts.Rate))
+	}
+	return http_adapter.RateLimit(opts)
+}
+
+func instanceHTTPAdapter() (result http_adapter.IHTTP) {
+	newAdapter, ok := http_adapter.Adapters[os.Getenv("HTTP_ROUTER")]
+	if !ok {
+		newAdapter = http_adapter.NewGorillaMux
+	}
+	result, _ = newAdapter()
+	return
+}
+
 func instanceDB() (result *sql.DB) {
 	result, _ = database.NewDatabaseConnection()
 	return
 }
 
-func instanceControllerMovie(db *sql.DB) (result controller_interfaces.IGenericController[model_movie.Movie]) {
-	result, _ = controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+func instanceControllerCinema(db *sql.DB, l *slog.Logger, m *metrics.Metrics, tp trace.TracerProvider) (result controller_interfaces.IGenericController[model_cinema.Cinema]) {
+	result, _ = controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: db, Logger: l})
+	result = metrics.NewInstrumentedController(&metrics.InstrumentedController[model_cinema.Cinema]{Name: "cinema", Metrics: m, Controller: result})
+	result = tracing.NewTracedController(&tracing.TracedController[model_cinema.Cinema]{Name: "cinema", TracerProvider: tp, Controller: result})
+	return
+}
+
+func instanceControllerMovie(db *sql.DB, l *slog.Logger, m *metrics.Metrics, tp trace.TracerProvider) (result controller_interfaces.IGenericController[model_movie.Movie]) {
+	result, _ = controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db, Logger: l})
+	result = metrics.NewInstrumentedController(&metrics.InstrumentedController[model_movie.Movie]{Name: "movie", Metrics: m, Controller: result})
+	result = tracing.NewTracedController(&tracing.TracedController[model_movie.Movie]{Name: "movie", TracerProvider: tp, Controller: result})
+	return
+}
+
+func instanceControllerUser(db *sql.DB, l *slog.Logger) (result controller_interfaces.IUserController) {
+	result, _ = controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db, Logger: l})
+	return
+}
+
+func ensureAdminUser(cu controller_interfaces.IUserController) {
+	username := os.Getenv("ADMIN_USERNAME")
+	password := os.Getenv("ADMIN_PASSWORD")
+	if username == "" || password == "" {
+		return
+	}
+	ctx := context.Background()
+	_, err := cu.FindByUsername(ctx, username)
+	if err == nil {
+		return
+	}
+	user, err := model_user.NewUser(&model_user.User{Username: username, Password: password, Roles: []string{"admin"}})
+	if err != nil {
+		log.Fatal("Error creating admin user, err: ", err)
+	}
+	_, err = cu.Create(ctx, user)
+	if err != nil {
+		log.Fatal("Error creating admin user, err: ", err)
+	}
+}
+
+func instanceControllerAPIKey(db *sql.DB, l *slog.Logger) (result controller_interfaces.IAPIKeyController) {
+	result, _ = controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db, Logger: l})
+	return
+}
+
+func instanceControllerExport(db *sql.DB, l *slog.Logger) (result controller_interfaces.IExportController) {
+	result, _ = controller_export.NewControllerExport(&controller_export.ControllerExport{Db: db, Logger: l})
+	return
+}
+
+func instanceControllerAudit(db *sql.DB, l *slog.Logger) (result controller_interfaces.IAuditController) {
+	result, _ = controller_audit.NewControllerAudit(&controller_audit.ControllerAudit{Db: db, Logger: l})
 	return
 }
 
-func instanceControllerRoom(db *sql.DB, cm controller_interfaces.IGenericController[model_movie.Movie]) (result controller_interfaces.IGenericController[model_room.Room]) {
-	result, _ = controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: cm})
+func apiKeyPrincipal(ctx context.Context, ck controller_interfaces.IAPIKeyController, key string) (result *auth.Principal, err error) {
+	prefix, err := model_apikey.ParsePrefix(key)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := ck.FindByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if !apiKey.Matches(key) || apiKey.IsRevoked() {
+		return nil, errors.New("invalid api key")
+	}
+	if apiKey.LastUsedAt == nil || time.Since(*apiKey.LastUsedAt) > time.Minute {
+		ck.TouchLastUsed(ctx, apiKey.Id)
+	}
+	return &auth.Principal{Subject: "apikey:" + apiKey.Id, Permissions: apiKey.Permissions, RoomIds: apiKey.RoomIds, CinemaId: apiKey.CinemaId}, nil
+}
+
+func instanceControllerRoom(db *sql.DB, cm controller_interfaces.IGenericController[model_movie.Movie], l *slog.Logger, m *metrics.Metrics, tp trace.TracerProvider) (result controller_interfaces.IGenericController[model_room.Room]) {
+	result, _ = controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: cm, Logger: l})
+	result = metrics.NewInstrumentedController(&metrics.InstrumentedController[model_room.Room]{Name: "room", Metrics: m, Controller: result})
+	result = tracing.NewTracedController(&tracing.TracedController[model_room.Room]{Name: "room", TracerProvider: tp, Controller: result})
 	return
 }
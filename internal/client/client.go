@@ -0,0 +1,193 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+)
+
+// Client talks to a running API; Movies and Rooms satisfy the same controller interfaces as the database-backed controllers.
+type Client struct {
+	BaseURL string
+	Token   string
+	APIKey  string
+	HTTP    *http.Client
+}
+
+func NewClient(c *Client) (result *Client) {
+	if c.HTTP == nil {
+		c.HTTP = http.DefaultClient
+	}
+	c.BaseURL = strings.TrimSuffix(c.BaseURL, "/")
+	return c
+}
+
+func (c *Client) Movies() controller_interfaces.IGenericController[model_movie.Movie] {
+	return &movies{c}
+}
+
+func (c *Client) Rooms() controller_interfaces.IGenericController[model_room.Room] {
+	return &rooms{c}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, target any) (err error) {
+	var payload io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+"/api/v1"+path, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.APIKey != "" {
+		req.Header.Set(auth.APIKeyHeader, c.APIKey)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		message := strings.TrimSpace(string(content))
+		if message == "" {
+			message = resp.Status
+		}
+		return errors.New(message)
+	}
+	switch target := target.(type) {
+	case nil:
+		return nil
+	case *string:
+		*target = string(content)
+		return nil
+	case *bool:
+		*target, err = strconv.ParseBool(strings.TrimSpace(string(content)))
+		return err
+	}
+	return json.Unmarshal(content, target)
+}
+
+type movies struct{ c *Client }
+
+type movieBody struct {
+	Name              string `json:"name"`
+	Director          string `json:"director"`
+	Year              uint16 `json:"year"`
+	DurationInSeconds uint16 `json:"durationInSeconds"`
+}
+
+func newMovieBody(m *model_movie.Movie) *movieBody {
+	return &movieBody{Name: m.Name, Director: m.Director, Year: m.Year, DurationInSeconds: m.DurationInSeconds}
+}
+
+func (ms *movies) Create(ctx context.Context, m *model_movie.Movie) (result string, err error) {
+	err = ms.c.do(ctx, http.MethodPost, "/movies", newMovieBody(m), &result)
+	m.Id = result
+	return
+}
+
+func (ms *movies) FindBy(ctx context.Context, id string) (result *model_movie.Movie, err error) {
+	result = &model_movie.Movie{}
+	err = ms.c.do(ctx, http.MethodGet, "/movies/"+id, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (ms *movies) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[model_movie.Movie], err error) {
+	result = &controller_interfaces.FindAllResponse[model_movie.Movie]{}
+	err = ms.c.do(ctx, http.MethodGet, fmt.Sprintf("/movies/all/%d", page), nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (ms *movies) UpdateBy(ctx context.Context, id string, m *model_movie.Movie) (result bool, err error) {
+	err = ms.c.do(ctx, http.MethodPut, "/movies/"+id, newMovieBody(m), &result)
+	return
+}
+
+func (ms *movies) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	err = ms.c.do(ctx, http.MethodDelete, "/movies/"+id, nil, &result)
+	return
+}
+
+type rooms struct{ c *Client }
+
+type roomBody struct {
+	CinemaId    string   `json:"cinemaId"`
+	Number      uint16   `json:"number"`
+	Description string   `json:"description"`
+	MoviesId    []string `json:"moviesId"`
+}
+
+func newRoomBody(r *model_room.Room) *roomBody {
+	body := &roomBody{CinemaId: r.CinemaId, Number: r.Number, Description: r.Description, MoviesId: []string{}}
+	for _, movie := range r.Movies {
+		body.MoviesId = append(body.MoviesId, movie.Id)
+	}
+	return body
+}
+
+func (rs *rooms) Create(ctx context.Context, r *model_room.Room) (result string, err error) {
+	err = rs.c.do(ctx, http.MethodPost, "/rooms", newRoomBody(r), &result)
+	r.Id = result
+	return
+}
+
+func (rs *rooms) FindBy(ctx context.Context, id string) (result *model_room.Room, err error) {
+	result = &model_room.Room{}
+	err = rs.c.do(ctx, http.MethodGet, "/rooms/"+id, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (rs *rooms) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[model_room.Room], err error) {
+	result = &controller_interfaces.FindAllResponse[model_room.Room]{}
+	path := fmt.Sprintf("/rooms/all/%d", page)
+	if !controller_interfaces.MoviesSkipped(ctx) {
+		path += "?expand=movies"
+	}
+	err = rs.c.do(ctx, http.MethodGet, path, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (rs *rooms) UpdateBy(ctx context.Context, id string, r *model_room.Room) (result bool, err error) {
+	err = rs.c.do(ctx, http.MethodPut, "/rooms/"+id, newRoomBody(r), &result)
+	return
+}
+
+func (rs *rooms) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	err = rs.c.do(ctx, http.MethodDelete, "/rooms/"+id, nil, &result)
+	return
+}
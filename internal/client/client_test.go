@@ -0,0 +1,73 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/client"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSpeaksTheAPI(t *testing.T) {
+	var requests []string
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Authorization"))
+		body := map[string]any{}
+		if content, _ := io.ReadAll(r.Body); len(content) > 0 {
+			json.Unmarshal(content, &body)
+		}
+		bodies = append(bodies, body)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v1/movies":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("movie-1"))
+		case "GET /api/v1/movies/movie-1":
+			w.Write([]byte(`{"id":"movie-1","name":"matrix","director":"wachowski","year":1999,"durationInSeconds":8160,"durationInHours":"02:16:00"}`))
+		case "GET /api/v1/rooms/all/2":
+			w.Write([]byte(`{"total":11,"page":2,"registers":[{"id":"room-1","cinemaId":"cinema-1","number":1,"description":"one","movies":[{"id":"movie-1"}]}]}`))
+		case "PUT /api/v1/rooms/room-1":
+			w.Write([]byte("true"))
+		default:
+			http.Error(w, "room not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewClient(&client.Client{BaseURL: server.URL + "/", Token: "token"})
+	ctx := context.Background()
+
+	movie := &model_movie.Movie{Name: "matrix", Director: "wachowski", Year: 1999, DurationInSeconds: 8160}
+	id, err := c.Movies().Create(ctx, movie)
+	assert.Nil(t, err)
+	assert.Equal(t, "movie-1", id)
+	assert.Equal(t, "movie-1", movie.Id)
+	assert.Equal(t, float64(8160), bodies[0]["durationInSeconds"])
+
+	found, err := c.Movies().FindBy(ctx, "movie-1")
+	assert.Nil(t, err)
+	assert.Equal(t, &model_movie.Movie{Id: "movie-1", Name: "matrix", Director: "wachowski", Year: 1999, DurationInSeconds: 8160}, found)
+
+	rooms, err := c.Rooms().FindAll(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(11), rooms.Total)
+	assert.Equal(t, "cinema-1", rooms.Registers[0].CinemaId)
+	assert.Equal(t, "movie-1", rooms.Registers[0].Movies[0].Id)
+
+	updated, err := c.Rooms().UpdateBy(ctx, "room-1", &model_room.Room{Number: 1, Description: "one", Movies: []*model_movie.Movie{found}})
+	assert.Nil(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, []any{"movie-1"}, bodies[3]["moviesId"])
+
+	_, err = c.Rooms().FindBy(ctx, "missing")
+	assert.EqualError(t, err, "room not found")
+
+	assert.Equal(t, "POST /api/v1/movies Bearer token", requests[0])
+	assert.Equal(t, "GET /api/v1/rooms/all/2?expand=movies Bearer token", requests[2])
+}
@@ -0,0 +1,237 @@
+package controller_apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/model/apikey"
+)
+
+const selectColumns = `id, name, prefix, key_hash, permissions, room_ids, cinema_id, created_at, last_used_at, revoked_at`
+
+type ControllerAPIKey struct {
+	Db     *sql.DB
+	Logger *slog.Logger
+}
+
+func NewControllerAPIKey(ck *ControllerAPIKey) (result controller_interfaces.IAPIKeyController, err error) {
+	if ck.Logger == nil {
+		ck.Logger = slog.Default()
+	}
+	result = ck
+	return
+}
+
+func (ck *ControllerAPIKey) Create(ctx context.Context, k *model_apikey.APIKey) (result string, err error) {
+	if k.KeyHash == "" {
+		return "", errors.New("api key must be generated before being stored")
+	}
+	query := `
+		INSERT INTO api_keys(id, name, prefix, key_hash, permissions, room_ids, cinema_id, created_at)
+		VALUES(?,?,?,?,?,?,?,?)
+	`
+	k.Id = uuid.NewString()
+	k.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	_, err = ck.Db.ExecContext(ctx, query, &k.Id, &k.Name, &k.Prefix, &k.KeyHash, strings.Join(k.Permissions, ","), strings.Join(k.RoomIds, ","), nullable(k.CinemaId), &k.CreatedAt)
+	if err != nil {
+		return "", ck.sqlError(ctx, "create", err)
+	}
+
+	return k.Id, nil
+}
+
+func (ck *ControllerAPIKey) FindBy(ctx context.Context, id string) (result *model_apikey.APIKey, err error) {
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `SELECT ` + selectColumns + ` FROM api_keys ` + where + ` LIMIT 1`
+	return ck.findOne(ctx, "find_by", query, args...)
+}
+
+// FindByPrefix authenticates keys, before any caller or cinema is known, so it is the one lookup left unscoped.
+func (ck *ControllerAPIKey) FindByPrefix(ctx context.Context, prefix string) (result *model_apikey.APIKey, err error) {
+	query := `SELECT ` + selectColumns + ` FROM api_keys WHERE prefix = ? LIMIT 1`
+	return ck.findOne(ctx, "find_by_prefix", query, prefix)
+}
+
+func (ck *ControllerAPIKey) findOne(ctx context.Context, operation, query string, args ...any) (result *model_apikey.APIKey, err error) {
+	rows, err := ck.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, ck.sqlError(ctx, operation, err)
+	}
+	defer rows.Close()
+	result = &model_apikey.APIKey{}
+	for rows.Next() {
+		scan(rows, result)
+	}
+	if result.Id == "" {
+		return nil, errors.New("api key not found")
+	}
+
+	return
+}
+
+func (ck *ControllerAPIKey) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[model_apikey.APIKey], err error) {
+	where, args := tenant.Where(ctx, "", "cinema_id")
+	query := `SELECT ` + selectColumns + ` FROM api_keys ` + where + ` ORDER BY created_at LIMIT ? OFFSET ?`
+	limit := controller_interfaces.PageSize
+	offset := limit * (page - 1)
+	rows, err := ck.Db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, ck.sqlError(ctx, "find_all", err)
+	}
+	defer rows.Close()
+	result = &controller_interfaces.FindAllResponse[model_apikey.APIKey]{}
+	for rows.Next() {
+		var target model_apikey.APIKey
+		scan(rows, &target)
+		result.Registers = append(result.Registers, &target)
+	}
+	result.Total, err = ck.GetTotal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Page = page
+	return
+}
+
+func (ck *ControllerAPIKey) GetTotal(ctx context.Context) (result uint32, err error) {
+	where, args := tenant.Where(ctx, "", "cinema_id")
+	query := `SELECT COUNT(1) FROM api_keys ` + where
+	rows, err := ck.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, ck.sqlError(ctx, "get_total", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&result)
+	}
+	return
+}
+
+func (ck *ControllerAPIKey) UpdateBy(ctx context.Context, id string, k *model_apikey.APIKey) (result bool, err error) {
+	_, err = ck.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `
+		UPDATE api_keys
+		SET
+			name = ?,
+			permissions = ?,
+			room_ids = ?,
+			cinema_id = ?
+		` + where
+	_, err = ck.Db.ExecContext(ctx, query, append([]any{&k.Name, strings.Join(k.Permissions, ","), strings.Join(k.RoomIds, ","), nullable(k.CinemaId)}, args...)...)
+	if err != nil {
+		return false, ck.sqlError(ctx, "update_by", err)
+	}
+
+	return true, nil
+}
+
+func (ck *ControllerAPIKey) Revoke(ctx context.Context, id string) (result bool, err error) {
+	k, err := ck.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if k.IsRevoked() {
+		return true, nil
+	}
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `UPDATE api_keys SET revoked_at = ? ` + where
+	_, err = ck.Db.ExecContext(ctx, query, append([]any{time.Now().UTC()}, args...)...)
+	if err != nil {
+		return false, ck.sqlError(ctx, "revoke", err)
+	}
+
+	return true, nil
+}
+
+func (ck *ControllerAPIKey) Rotate(ctx context.Context, id string) (result string, err error) {
+	k, err := ck.FindBy(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if k.IsRevoked() {
+		return "", errors.New("api key is revoked")
+	}
+	result, err = k.Generate()
+	if err != nil {
+		return "", err
+	}
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `UPDATE api_keys SET prefix = ?, key_hash = ? ` + where
+	_, err = ck.Db.ExecContext(ctx, query, append([]any{&k.Prefix, &k.KeyHash}, args...)...)
+	if err != nil {
+		return "", ck.sqlError(ctx, "rotate", err)
+	}
+
+	return
+}
+
+func (ck *ControllerAPIKey) TouchLastUsed(ctx context.Context, id string) (err error) {
+	query := `UPDATE api_keys SET last_used_at = ? WHERE id = ?`
+	_, err = ck.Db.ExecContext(ctx, query, time.Now().UTC(), id)
+	if err != nil {
+		return ck.sqlError(ctx, "touch_last_used", err)
+	}
+	return
+}
+
+func (ck *ControllerAPIKey) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	_, err = ck.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `DELETE FROM api_keys ` + where
+	_, err = ck.Db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, ck.sqlError(ctx, "delete_by", err)
+	}
+
+	return true, nil
+}
+
+func scan(rows *sql.Rows, k *model_apikey.APIKey) {
+	var permissions, roomIds string
+	var cinemaId sql.NullString
+	var lastUsedAt, revokedAt sql.NullTime
+	rows.Scan(&k.Id, &k.Name, &k.Prefix, &k.KeyHash, &permissions, &roomIds, &cinemaId, &k.CreatedAt, &lastUsedAt, &revokedAt)
+	k.CinemaId = cinemaId.String
+	k.Permissions = split(permissions)
+	k.RoomIds = split(roomIds)
+	k.LastUsedAt = nil
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	k.RevokedAt = nil
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+}
+
+func nullable(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func split(value string) (result []string) {
+	for _, v := range strings.Split(value, ",") {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return
+}
+
+func (ck *ControllerAPIKey) sqlError(ctx context.Context, operation string, err error) error {
+	ck.Logger.ErrorContext(ctx, "sql error", "entity", "api_key", "operation", operation, "err", err)
+	return err
+}
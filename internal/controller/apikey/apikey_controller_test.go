@@ -0,0 +1,173 @@
+package controller_apikey_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	controller_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/controller/apikey"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/model/apikey"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceAPIKey() (result *model_apikey.APIKey, key string) {
+	result, _ = model_apikey.NewAPIKey(&model_apikey.APIKey{
+		Name:        "kiosk",
+		Permissions: []string{"rooms:read", "movies:read"},
+		RoomIds:     []string{"room-a"},
+	})
+	key, _ = result.Generate()
+	return
+}
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	return
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	apiKey, _ := instanceAPIKey()
+	result, err := controllerAPIKey.Create(context.Background(), apiKey)
+	assert.Nil(t, err)
+	assert.Greater(t, len(result), 10)
+}
+
+func TestFindByPrefix(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	apiKey, key := instanceAPIKey()
+	id, _ := controllerAPIKey.Create(context.Background(), apiKey)
+	prefix, _ := model_apikey.ParsePrefix(key)
+	result, err := controllerAPIKey.FindByPrefix(context.Background(), prefix)
+	assert.Nil(t, err)
+	assert.Equal(t, id, result.Id)
+	assert.True(t, result.Matches(key))
+	assert.Equal(t, []string{"rooms:read", "movies:read"}, result.Permissions)
+	assert.Equal(t, []string{"room-a"}, result.RoomIds)
+	assert.Nil(t, result.LastUsedAt)
+	assert.Nil(t, result.RevokedAt)
+}
+
+func TestFindAll(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	apiKey, _ := instanceAPIKey()
+	id, _ := controllerAPIKey.Create(context.Background(), apiKey)
+	result, err := controllerAPIKey.FindAll(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), result.Total)
+	assert.Equal(t, id, result.Registers[0].Id)
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	apiKey, _ := instanceAPIKey()
+	id, _ := controllerAPIKey.Create(context.Background(), apiKey)
+	apiKey.Name = "partner"
+	apiKey.Permissions = []string{"movies:read"}
+	apiKey.RoomIds = nil
+	result, err := controllerAPIKey.UpdateBy(context.Background(), id, apiKey)
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+	updated, _ := controllerAPIKey.FindBy(context.Background(), id)
+	assert.Equal(t, "partner", updated.Name)
+	assert.Equal(t, []string{"movies:read"}, updated.Permissions)
+	assert.Nil(t, updated.RoomIds)
+}
+
+func TestRevoke(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	apiKey, _ := instanceAPIKey()
+	id, _ := controllerAPIKey.Create(context.Background(), apiKey)
+	result, err := controllerAPIKey.Revoke(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+	revoked, _ := controllerAPIKey.FindBy(context.Background(), id)
+	assert.True(t, revoked.IsRevoked())
+	_, err = controllerAPIKey.Rotate(context.Background(), id)
+	assert.EqualError(t, err, "api key is revoked")
+}
+
+func TestRotate(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	apiKey, oldKey := instanceAPIKey()
+	id, _ := controllerAPIKey.Create(context.Background(), apiKey)
+	newKey, err := controllerAPIKey.Rotate(context.Background(), id)
+	assert.Nil(t, err)
+	rotated, _ := controllerAPIKey.FindBy(context.Background(), id)
+	assert.True(t, rotated.Matches(newKey))
+	assert.False(t, rotated.Matches(oldKey))
+}
+
+func TestTouchLastUsed(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	apiKey, _ := instanceAPIKey()
+	id, _ := controllerAPIKey.Create(context.Background(), apiKey)
+	err := controllerAPIKey.TouchLastUsed(context.Background(), id)
+	assert.Nil(t, err)
+	touched, _ := controllerAPIKey.FindBy(context.Background(), id)
+	assert.NotNil(t, touched.LastUsedAt)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	apiKey, _ := instanceAPIKey()
+	id, _ := controllerAPIKey.Create(context.Background(), apiKey)
+	result, err := controllerAPIKey.DeleteBy(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+}
+
+func TestScopedToCinema(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	db.Exec("INSERT INTO cinemas(id, name) VALUES('cinema-a', 'a'), ('cinema-b', 'b')")
+	controllerAPIKey, _ := controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	own, _ := instanceAPIKey()
+	own.CinemaId = "cinema-a"
+	controllerAPIKey.Create(context.Background(), own)
+	other, otherKey := instanceAPIKey()
+	other.CinemaId = "cinema-b"
+	controllerAPIKey.Create(context.Background(), other)
+	global, _ := instanceAPIKey()
+	controllerAPIKey.Create(context.Background(), global)
+
+	ctx := tenant.WithCinema(context.Background(), "cinema-a")
+	all, err := controllerAPIKey.FindAll(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), all.Total)
+	assert.Equal(t, own.Id, all.Registers[0].Id)
+	for _, id := range []string{other.Id, global.Id} {
+		_, err = controllerAPIKey.FindBy(ctx, id)
+		assert.EqualError(t, err, "api key not found")
+		_, err = controllerAPIKey.UpdateBy(ctx, id, own)
+		assert.EqualError(t, err, "api key not found")
+		_, err = controllerAPIKey.Rotate(ctx, id)
+		assert.EqualError(t, err, "api key not found")
+		_, err = controllerAPIKey.Revoke(ctx, id)
+		assert.EqualError(t, err, "api key not found")
+		_, err = controllerAPIKey.DeleteBy(ctx, id)
+		assert.EqualError(t, err, "api key not found")
+	}
+	untouched, _ := controllerAPIKey.FindBy(context.Background(), other.Id)
+	assert.True(t, untouched.Matches(otherKey))
+	assert.False(t, untouched.IsRevoked())
+	assert.Equal(t, "cinema-b", untouched.CinemaId)
+}
@@ -0,0 +1,146 @@
+package controller_audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_audit "github.com/rochaeduardo997/irede_golang_dev/internal/model/audit"
+)
+
+const SystemActor = "system"
+
+type ControllerAudit struct {
+	Db     *sql.DB
+	Logger *slog.Logger
+}
+
+func NewControllerAudit(ca *ControllerAudit) (result controller_interfaces.IAuditController, err error) {
+	if ca.Logger == nil {
+		ca.Logger = slog.Default()
+	}
+	result = ca
+	return
+}
+
+// Record writes the entry inside the caller's transaction so the audit trail commits or rolls back with the change.
+// The entry belongs to the cinema ctx is scoped to, which callers set to the cinema owning the entity when it has one.
+func Record(ctx context.Context, tx *sql.Tx, action, entity, entityId string, before, after any) (err error) {
+	entry, err := model_audit.NewEntry(&model_audit.Entry{
+		Id:        uuid.NewString(),
+		Actor:     actor(ctx),
+		Action:    action,
+		Entity:    entity,
+		EntityId:  entityId,
+		CreatedAt: time.Now().UTC(),
+	}, before, after)
+	if err != nil {
+		return err
+	}
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO audit_logs(id, actor, action, entity, entity_id, changes, created_at, cinema_id)
+		VALUES(?,?,?,?,?,?,?,?)
+	`
+	cinemaId := tenant.CinemaFrom(ctx)
+	_, err = tx.ExecContext(ctx, query, &entry.Id, &entry.Actor, &entry.Action, &entry.Entity, &entry.EntityId, changes, &entry.CreatedAt, sql.NullString{String: cinemaId, Valid: cinemaId != ""})
+	return
+}
+
+func actor(ctx context.Context) string {
+	principal := auth.PrincipalFrom(ctx)
+	if principal == nil || principal.Subject == "" {
+		return SystemActor
+	}
+	return principal.Subject
+}
+
+func (ca *ControllerAudit) FindAll(ctx context.Context, filter *model_audit.Filter, page uint16) (result *controller_interfaces.FindAllResponse[model_audit.Entry], err error) {
+	where, args := conditions(ctx, filter)
+	query := `
+		SELECT id, actor, action, entity, entity_id, changes, created_at
+		FROM audit_logs
+		` + where + `
+		ORDER BY created_at DESC
+		LIMIT ?
+		OFFSET ?
+	`
+	limit := controller_interfaces.PageSize
+	offset := limit * (page - 1)
+	rows, err := ca.Db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, ca.sqlError(ctx, "find_all", err)
+	}
+	defer rows.Close()
+	result = &controller_interfaces.FindAllResponse[model_audit.Entry]{}
+	for rows.Next() {
+		var target model_audit.Entry
+		var changes []byte
+		rows.Scan(&target.Id, &target.Actor, &target.Action, &target.Entity, &target.EntityId, &changes, &target.CreatedAt)
+		json.Unmarshal(changes, &target.Changes)
+		result.Registers = append(result.Registers, &target)
+	}
+	result.Total, err = ca.GetTotal(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result.Page = page
+	return
+}
+
+func (ca *ControllerAudit) GetTotal(ctx context.Context, filter *model_audit.Filter) (result uint32, err error) {
+	where, args := conditions(ctx, filter)
+	query := `SELECT COUNT(1) FROM audit_logs ` + where
+	rows, err := ca.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, ca.sqlError(ctx, "get_total", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&result)
+	}
+	return
+}
+
+func conditions(ctx context.Context, filter *model_audit.Filter) (result string, args []any) {
+	clauses := []string{}
+	if cinemaId := tenant.CinemaFrom(ctx); cinemaId != "" {
+		clauses = append(clauses, "cinema_id = ?")
+		args = append(args, cinemaId)
+	}
+	if filter.Entity != "" {
+		clauses = append(clauses, "entity = ?")
+		args = append(args, filter.Entity)
+	}
+	if filter.Actor != "" {
+		clauses = append(clauses, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if !filter.From.IsZero() {
+		clauses = append(clauses, "created_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		clauses = append(clauses, "created_at <= ?")
+		args = append(args, filter.To.UTC())
+	}
+	if len(clauses) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(clauses, " AND "), args
+}
+
+func (ca *ControllerAudit) sqlError(ctx context.Context, operation string, err error) error {
+	ca.Logger.ErrorContext(ctx, "sql error", "entity", "audit", "operation", operation, "err", err)
+	return err
+}
@@ -0,0 +1,93 @@
+package controller_audit_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	controller_audit "github.com/rochaeduardo997/irede_golang_dev/internal/controller/audit"
+	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
+	controller_room "github.com/rochaeduardo997/irede_golang_dev/internal/controller/room"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/seed"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_audit "github.com/rochaeduardo997/irede_golang_dev/internal/model/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	return
+}
+
+func TestRecordMovieMutations(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	controllerAudit, _ := controller_audit.NewControllerAudit(&controller_audit.ControllerAudit{Db: db})
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "user-id"})
+
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := controllerMovie.Create(ctx, movie)
+	movie.Name = "new name"
+	controllerMovie.UpdateBy(ctx, id, movie)
+	controllerMovie.DeleteBy(context.Background(), id)
+
+	result, err := controllerAudit.FindAll(context.Background(), &model_audit.Filter{Entity: "movie"}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), result.Total)
+	actions := []string{}
+	for _, entry := range result.Registers {
+		actions = append(actions, entry.Action)
+		assert.Equal(t, id, entry.EntityId)
+	}
+	assert.ElementsMatch(t, []string{"create", "update", "delete"}, actions)
+
+	result, err = controllerAudit.FindAll(context.Background(), &model_audit.Filter{Actor: "user-id"}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), result.Total)
+
+	result, _ = controllerAudit.FindAll(context.Background(), &model_audit.Filter{Actor: controller_audit.SystemActor}, 1)
+	assert.Equal(t, model_audit.ActionDelete, result.Registers[0].Action)
+	assert.Equal(t, model_audit.Change{Before: "new name"}, result.Registers[0].Changes["Name"])
+}
+
+func TestFindAllByTimeRange(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	controllerAudit, _ := controller_audit.NewControllerAudit(&controller_audit.ControllerAudit{Db: db})
+	controllerMovie.Create(context.Background(), seed.NewGenerator(seed.DefaultSeed).Movie())
+
+	result, err := controllerAudit.FindAll(context.Background(), &model_audit.Filter{From: time.Now().Add(-time.Minute), To: time.Now().Add(time.Minute)}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), result.Total)
+
+	result, err = controllerAudit.FindAll(context.Background(), &model_audit.Filter{To: time.Now().Add(-time.Hour)}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), result.Total)
+}
+
+func TestFindAllScopedToCinema(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	db.Exec("INSERT INTO cinemas(id, name) VALUES('cinema-a', 'a'), ('cinema-b', 'b')")
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
+	controllerAudit, _ := controller_audit.NewControllerAudit(&controller_audit.ControllerAudit{Db: db})
+	g := seed.NewGenerator(seed.DefaultSeed)
+	own := g.Room("cinema-a")
+	controllerRoom.Create(context.Background(), own)
+	controllerRoom.Create(context.Background(), g.Room("cinema-b"))
+	controllerMovie.Create(context.Background(), g.Movie())
+
+	result, err := controllerAudit.FindAll(tenant.WithCinema(context.Background(), "cinema-a"), &model_audit.Filter{}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), result.Total)
+	assert.Equal(t, own.Id, result.Registers[0].EntityId)
+
+	result, _ = controllerAudit.FindAll(context.Background(), &model_audit.Filter{}, 1)
+	assert.Equal(t, uint32(3), result.Total)
+}
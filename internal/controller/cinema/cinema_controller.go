@@ -0,0 +1,165 @@
+package controller_cinema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+)
+
+type ControllerCinema struct {
+	Db     *sql.DB
+	Logger *slog.Logger
+}
+
+func NewControllerCinema(cc *ControllerCinema) (result controller_interfaces.IGenericController[model_cinema.Cinema], err error) {
+	if cc.Logger == nil {
+		cc.Logger = slog.Default()
+	}
+	result = cc
+	return
+}
+
+func (cc *ControllerCinema) Create(ctx context.Context, c *model_cinema.Cinema) (result string, err error) {
+	query := `
+		INSERT INTO cinemas(id, name, city)
+		VALUES(?,?,?)
+	`
+	c.Id = uuid.NewString()
+	_, err = cc.Db.ExecContext(ctx, query, &c.Id, &c.Name, &c.City)
+	if err != nil {
+		return "", cc.sqlError(ctx, "create", err)
+	}
+
+	return c.Id, nil
+}
+
+func (cc *ControllerCinema) FindBy(ctx context.Context, id string) (result *model_cinema.Cinema, err error) {
+	if cinemaId := tenant.CinemaFrom(ctx); cinemaId != "" && cinemaId != id {
+		return nil, errors.New("cinema not found")
+	}
+	query := `
+		SELECT id, name, city
+		FROM cinemas
+		WHERE id = ?
+		LIMIT 1
+	`
+	rows, err := cc.Db.QueryContext(ctx, query, &id)
+	if err != nil {
+		return nil, cc.sqlError(ctx, "find_by", err)
+	}
+	defer rows.Close()
+	result = &model_cinema.Cinema{}
+	for rows.Next() {
+		rows.Scan(&result.Id, &result.Name, &result.City)
+	}
+	if result.Id == "" {
+		return nil, errors.New("cinema not found")
+	}
+	err = result.IsValid()
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+func (cc *ControllerCinema) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[model_cinema.Cinema], err error) {
+	where, args := scope(ctx)
+	query := `
+		SELECT id, name, city
+		FROM cinemas
+		` + where + `
+		LIMIT ?
+		OFFSET ?
+	`
+	limit := controller_interfaces.PageSize
+	offset := limit * (page - 1)
+	rows, err := cc.Db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, cc.sqlError(ctx, "find_all", err)
+	}
+	defer rows.Close()
+	result = &controller_interfaces.FindAllResponse[model_cinema.Cinema]{}
+	for rows.Next() {
+		var target model_cinema.Cinema
+		rows.Scan(&target.Id, &target.Name, &target.City)
+		err = target.IsValid()
+		if err != nil {
+			continue
+		}
+		result.Registers = append(result.Registers, &target)
+	}
+	result.Total, err = cc.GetTotal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Page = page
+	return
+}
+
+func (cc *ControllerCinema) GetTotal(ctx context.Context) (result uint32, err error) {
+	where, args := scope(ctx)
+	query := `SELECT COUNT(1) FROM cinemas ` + where
+	rows, err := cc.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, cc.sqlError(ctx, "get_total", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&result)
+	}
+	return
+}
+
+func (cc *ControllerCinema) UpdateBy(ctx context.Context, id string, c *model_cinema.Cinema) (result bool, err error) {
+	_, err = cc.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	query := `
+		UPDATE cinemas
+		SET
+			name = ?,
+			city = ?
+		WHERE id = ?;
+	`
+	_, err = cc.Db.ExecContext(ctx, query, &c.Name, &c.City, id)
+	if err != nil {
+		return false, cc.sqlError(ctx, "update_by", err)
+	}
+
+	return true, nil
+}
+
+func (cc *ControllerCinema) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	_, err = cc.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	query := `DELETE FROM cinemas WHERE id = ?`
+	_, err = cc.Db.ExecContext(ctx, query, &id)
+	if err != nil {
+		return false, cc.sqlError(ctx, "delete_by", err)
+	}
+
+	return true, nil
+}
+
+func scope(ctx context.Context) (result string, args []any) {
+	cinemaId := tenant.CinemaFrom(ctx)
+	if cinemaId == "" {
+		return "", nil
+	}
+	return "WHERE id = ?", []any{cinemaId}
+}
+
+func (cc *ControllerCinema) sqlError(ctx context.Context, operation string, err error) error {
+	cc.Logger.ErrorContext(ctx, "sql error", "entity", "cinema", "operation", operation, "err", err)
+	return err
+}
@@ -0,0 +1,98 @@
+package controller_cinema_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	controller_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/controller/cinema"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceCinema() (result *model_cinema.Cinema) {
+	result, _ = model_cinema.NewCinema(&model_cinema.Cinema{
+		Id:   "id",
+		Name: "name",
+		City: "city",
+	})
+	return
+}
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	return
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerCinema, _ := controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: db})
+	result, err := controllerCinema.Create(context.Background(), instanceCinema())
+	assert.Nil(t, err)
+	assert.Greater(t, len(result), 10)
+}
+
+func TestFindById(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerCinema, _ := controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: db})
+	cinema := instanceCinema()
+	id, _ := controllerCinema.Create(context.Background(), cinema)
+	result, err := controllerCinema.FindBy(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, cinema, result)
+}
+
+func TestFindAll(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerCinema, _ := controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: db})
+	cinema := instanceCinema()
+	controllerCinema.Create(context.Background(), cinema)
+	result, err := controllerCinema.FindAll(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), result.Total)
+	assert.Equal(t, cinema, result.Registers[0])
+}
+
+func TestFindAllScopedToCinema(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerCinema, _ := controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: db})
+	id, _ := controllerCinema.Create(context.Background(), instanceCinema())
+	otherId, _ := controllerCinema.Create(context.Background(), instanceCinema())
+	ctx := tenant.WithCinema(context.Background(), id)
+	result, err := controllerCinema.FindAll(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), result.Total)
+	assert.Equal(t, id, result.Registers[0].Id)
+	_, err = controllerCinema.FindBy(ctx, otherId)
+	assert.EqualError(t, err, "cinema not found")
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerCinema, _ := controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: db})
+	cinema := instanceCinema()
+	id, _ := controllerCinema.Create(context.Background(), cinema)
+	cinema.Name = "new_name"
+	result, err := controllerCinema.UpdateBy(context.Background(), id, cinema)
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+	updated, _ := controllerCinema.FindBy(context.Background(), id)
+	assert.Equal(t, "new_name", updated.Name)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerCinema, _ := controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: db})
+	id, _ := controllerCinema.Create(context.Background(), instanceCinema())
+	result, err := controllerCinema.DeleteBy(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+}
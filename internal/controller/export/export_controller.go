@@ -0,0 +1,147 @@
+package controller_export
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
+	controller_room "github.com/rochaeduardo997/irede_golang_dev/internal/controller/room"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+)
+
+// ControllerExport walks whole tables row by row, handing each register to a callback instead of paging them into memory.
+type ControllerExport struct {
+	Db     *sql.DB
+	Logger *slog.Logger
+}
+
+func NewControllerExport(ce *ControllerExport) (result controller_interfaces.IExportController, err error) {
+	if ce.Logger == nil {
+		ce.Logger = slog.Default()
+	}
+	result = ce
+	return
+}
+
+func (ce *ControllerExport) Movies(ctx context.Context, fn func(m *model_movie.Movie) error) (err error) {
+	where, args := controller_movie.Filter(ctx)
+	query := `
+		SELECT id, name, director, year, duration_in_seconds
+		FROM movies
+		` + where + `
+		ORDER BY name, director, year
+	`
+	rows, err := ce.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return ce.sqlError(ctx, "movies", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var target model_movie.Movie
+		err = rows.Scan(&target.Id, &target.Name, &target.Director, &target.Year, &target.DurationInSeconds)
+		if err == nil {
+			err = fn(&target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (ce *ControllerExport) Rooms(ctx context.Context, cinemaId string, fn func(r *model_room.Room) error) (err error) {
+	where, args, err := filters(ctx, cinemaId, "", "")
+	if err != nil {
+		return err
+	}
+	query := `
+		SELECT id, fk_cinema_id, number, description
+		FROM rooms
+		` + where + `
+		ORDER BY fk_cinema_id, number
+	`
+	rows, err := ce.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return ce.sqlError(ctx, "rooms", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var target model_room.Room
+		err = rows.Scan(&target.Id, &target.CinemaId, &target.Number, &target.Description)
+		if err == nil {
+			err = fn(&target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (ce *ControllerExport) Lineups(ctx context.Context, cinemaId, roomId string, fn func(r *model_room.Room, m *model_movie.Movie) error) (err error) {
+	where, args, err := filters(ctx, cinemaId, roomId, "r.")
+	if err != nil {
+		return err
+	}
+	query := `
+		SELECT r.id, r.fk_cinema_id, r.number, r.description, m.id, m.name, m.director, m.year, m.duration_in_seconds
+		FROM room_movies rm
+		INNER JOIN rooms r ON r.id = rm.fk_room_id
+		INNER JOIN movies m ON m.id = rm.fk_movie_id
+		` + where + `
+		ORDER BY r.fk_cinema_id, r.number, m.name
+	`
+	rows, err := ce.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return ce.sqlError(ctx, "lineups", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var room model_room.Room
+		var movie model_movie.Movie
+		err = rows.Scan(&room.Id, &room.CinemaId, &room.Number, &room.Description, &movie.Id, &movie.Name, &movie.Director, &movie.Year, &movie.DurationInSeconds)
+		if err == nil {
+			err = fn(&room, &movie)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filters narrows rooms to the requested cinema and room, never past the caller's own cinema, and to the movie from
+// controller_interfaces.WithMovie.
+func filters(ctx context.Context, cinemaId, roomId, alias string) (where string, args []any, err error) {
+	cinemaId, err = tenant.Resolve(ctx, cinemaId)
+	if err != nil {
+		return "", nil, err
+	}
+	conditions := []string{}
+	if cinemaId != "" {
+		conditions = append(conditions, alias+"fk_cinema_id = ?")
+		args = append(args, cinemaId)
+	}
+	if roomId != "" {
+		conditions = append(conditions, alias+"id = ?")
+		args = append(args, roomId)
+	}
+	if movieId := controller_interfaces.MovieFrom(ctx); movieId != "" {
+		conditions = append(conditions, alias+controller_room.ShowingMovie)
+		args = append(args, movieId)
+	}
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	return
+}
+
+func (ce *ControllerExport) sqlError(ctx context.Context, operation string, err error) error {
+	ce.Logger.ErrorContext(ctx, "sql error", "entity", "export", "operation", operation, "err", err)
+	return err
+}
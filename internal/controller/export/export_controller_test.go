@@ -0,0 +1,44 @@
+package controller_export_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	controller_export "github.com/rochaeduardo997/irede_golang_dev/internal/controller/export"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	result.Exec("INSERT INTO cinemas(id, name) VALUES('cinema-id', 'cinema')")
+	result.Exec("INSERT INTO movies(id, name, director, year, duration_in_seconds) VALUES('matrix', 'matrix', 'wachowski', 1999, 5760), ('alien', 'alien', 'scott', 1979, 7020)")
+	result.Exec("INSERT INTO rooms(id, fk_cinema_id, number, description) VALUES('room-a', 'cinema-id', 1, 'a'), ('room-b', 'cinema-id', 2, 'b')")
+	result.Exec("INSERT INTO room_movies(fk_room_id, fk_movie_id) VALUES('room-a', 'matrix'), ('room-b', 'alien')")
+	return
+}
+
+func TestExportWithListingFilters(t *testing.T) {
+	t.Parallel()
+	ce, _ := controller_export.NewControllerExport(&controller_export.ControllerExport{Db: instanceDB(t)})
+
+	movies := []string{}
+	err := ce.Movies(controller_interfaces.WithRoom(context.Background(), "room-a"), func(m *model_movie.Movie) error {
+		movies = append(movies, m.Id)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"matrix"}, movies)
+
+	rooms := []string{}
+	err = ce.Rooms(controller_interfaces.WithMovie(context.Background(), "alien"), "", func(r *model_room.Room) error {
+		rooms = append(rooms, r.Id)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"room-b"}, rooms)
+}
@@ -0,0 +1,15 @@
+package controller_interfaces
+
+import (
+	"context"
+
+	model_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/model/apikey"
+)
+
+type IAPIKeyController interface {
+	IGenericController[model_apikey.APIKey]
+	FindByPrefix(ctx context.Context, prefix string) (result *model_apikey.APIKey, err error)
+	Revoke(ctx context.Context, id string) (result bool, err error)
+	Rotate(ctx context.Context, id string) (result string, err error)
+	TouchLastUsed(ctx context.Context, id string) (err error)
+}
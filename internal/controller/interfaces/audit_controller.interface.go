@@ -0,0 +1,11 @@
+package controller_interfaces
+
+import (
+	"context"
+
+	model_audit "github.com/rochaeduardo997/irede_golang_dev/internal/model/audit"
+)
+
+type IAuditController interface {
+	FindAll(ctx context.Context, filter *model_audit.Filter, page uint16) (result *FindAllResponse[model_audit.Entry], err error)
+}
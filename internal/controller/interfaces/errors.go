@@ -0,0 +1,13 @@
+package controller_interfaces
+
+import "fmt"
+
+// ConflictError reports that a unique constraint matched an existing record.
+type ConflictError struct {
+	Entity     string
+	ExistingId string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s already exists with id %s", e.Entity, e.ExistingId)
+}
@@ -0,0 +1,14 @@
+package controller_interfaces
+
+import (
+	"context"
+
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+)
+
+type IExportController interface {
+	Movies(ctx context.Context, fn func(m *model_movie.Movie) error) (err error)
+	Rooms(ctx context.Context, cinemaId string, fn func(r *model_room.Room) error) (err error)
+	Lineups(ctx context.Context, cinemaId, roomId string, fn func(r *model_room.Room, m *model_movie.Movie) error) (err error)
+}
@@ -0,0 +1,59 @@
+package controller_interfaces
+
+import "context"
+
+type movieKey struct{}
+
+// WithMovie narrows room listings to the rooms showing the movie; an empty id leaves ctx unfiltered.
+func WithMovie(ctx context.Context, movieId string) context.Context {
+	if movieId == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, movieKey{}, movieId)
+}
+
+func MovieFrom(ctx context.Context) (result string) {
+	result, _ = ctx.Value(movieKey{}).(string)
+	return
+}
+
+type roomKey struct{}
+
+// WithRoom narrows movie listings to the movies shown in the room; an empty id leaves ctx unfiltered.
+func WithRoom(ctx context.Context, roomId string) context.Context {
+	if roomId == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, roomKey{}, roomId)
+}
+
+func RoomFrom(ctx context.Context) (result string) {
+	result, _ = ctx.Value(roomKey{}).(string)
+	return
+}
+
+type roomsKey struct{}
+
+// WithRooms narrows room listings to the given rooms, e.g. those a room-scoped principal may read; an empty list leaves ctx unfiltered.
+func WithRooms(ctx context.Context, roomIds []string) context.Context {
+	if len(roomIds) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, roomsKey{}, roomIds)
+}
+
+func RoomsFrom(ctx context.Context) (result []string) {
+	result, _ = ctx.Value(roomsKey{}).([]string)
+	return
+}
+
+type withoutMoviesKey struct{}
+
+// WithoutMovies makes room lookups leave Movies empty instead of loading the association.
+func WithoutMovies(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutMoviesKey{}, true)
+}
+
+func MoviesSkipped(ctx context.Context) bool {
+	return ctx.Value(withoutMoviesKey{}) != nil
+}
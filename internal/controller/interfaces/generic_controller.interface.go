@@ -1,15 +1,28 @@
 package controller_interfaces
 
+import "context"
+
+// PageSize is how many registers a FindAll page holds.
+const PageSize uint16 = 10
+
 type FindAllResponse[T any] struct {
 	Total     uint32
 	Page      uint16
 	Registers []*T
 }
 
+// LastPage is the last page holding registers, 1 when there are none.
+func (r *FindAllResponse[T]) LastPage() uint16 {
+	if r.Total == 0 {
+		return 1
+	}
+	return uint16((r.Total + uint32(PageSize) - 1) / uint32(PageSize))
+}
+
 type IGenericController[T any] interface {
-	Create(m *T) (result string, err error)
-	FindBy(id string) (result *T, err error)
-	FindAll(page uint16) (result *FindAllResponse[T], err error)
-	UpdateBy(id string, m *T) (result bool, err error)
-	DeleteBy(id string) (result bool, err error)
+	Create(ctx context.Context, m *T) (result string, err error)
+	FindBy(ctx context.Context, id string) (result *T, err error)
+	FindAll(ctx context.Context, page uint16) (result *FindAllResponse[T], err error)
+	UpdateBy(ctx context.Context, id string, m *T) (result bool, err error)
+	DeleteBy(ctx context.Context, id string) (result bool, err error)
 }
@@ -0,0 +1,14 @@
+package controller_interfaces
+
+import (
+	"context"
+
+	model_user "github.com/rochaeduardo997/irede_golang_dev/internal/model/user"
+)
+
+type IUserController interface {
+	IGenericController[model_user.User]
+	FindByUsername(ctx context.Context, username string) (result *model_user.User, err error)
+	ChangePassword(ctx context.Context, id, password string) (result bool, err error)
+	SetDisabled(ctx context.Context, id string, disabled bool) (result bool, err error)
+}
@@ -1,49 +1,73 @@
 package controller_movie
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"log/slog"
 
 	"github.com/google/uuid"
+	controller_audit "github.com/rochaeduardo997/irede_golang_dev/internal/controller/audit"
 	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_audit "github.com/rochaeduardo997/irede_golang_dev/internal/model/audit"
 	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
 )
 
-type ControllerMovie struct{ Db *sql.DB }
+type ControllerMovie struct {
+	Db     *sql.DB
+	Logger *slog.Logger
+}
 
 func NewControllerMovie(cm *ControllerMovie) (result controller_interfaces.IGenericController[model_movie.Movie], err error) {
+	if cm.Logger == nil {
+		cm.Logger = slog.Default()
+	}
 	result = cm
 	return
 }
 
-func (cm *ControllerMovie) Create(m *model_movie.Movie) (result string, err error) {
+func (cm *ControllerMovie) Create(ctx context.Context, m *model_movie.Movie) (result string, err error) {
 	query := `
-		INSERT INTO movies(id, name, director, duration_in_seconds)
-		VALUES(?,?,?,?)
+		INSERT INTO movies(id, name, director, year, duration_in_seconds)
+		VALUES(?,?,?,?,?)
 	`
 	m.Id = uuid.NewString()
-	_, err = cm.Db.Query(query, &m.Id, &m.Name, &m.Director, &m.DurationInSeconds)
+	tx, err := database.BeginTx(ctx, cm.Db)
 	if err != nil {
-		return "", err
+		return "", cm.sqlError(ctx, "create", err)
+	}
+	_, err = tx.ExecContext(ctx, query, &m.Id, &m.Name, &m.Director, &m.Year, &m.DurationInSeconds)
+	if err == nil {
+		err = controller_audit.Record(ctx, tx.Tx, model_audit.ActionCreate, "movie", m.Id, nil, m)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		tx.Rollback()
+		return "", cm.conflict(ctx, m, cm.sqlError(ctx, "create", err))
 	}
 
 	return m.Id, nil
 }
 
-func (cm *ControllerMovie) FindBy(id string) (result *model_movie.Movie, err error) {
+func (cm *ControllerMovie) FindBy(ctx context.Context, id string) (result *model_movie.Movie, err error) {
 	query := `
-		SELECT id, name, director, duration_in_seconds
+		SELECT id, name, director, year, duration_in_seconds
 		FROM movies
 		WHERE id = ?
 		LIMIT 1
 	`
-	rows, err := cm.Db.Query(query, &id)
+	rows, err := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, &id)
 	if err != nil {
-		return nil, err
+		return nil, cm.sqlError(ctx, "find_by", err)
 	}
+	defer rows.Close()
 	result = &model_movie.Movie{}
 	for rows.Next() {
-		rows.Scan(&result.Id, &result.Name, &result.Director, &result.DurationInSeconds)
+		rows.Scan(&result.Id, &result.Name, &result.Director, &result.Year, &result.DurationInSeconds)
 	}
 	if result.Id == "" {
 		return nil, errors.New("movie not found")
@@ -56,30 +80,33 @@ func (cm *ControllerMovie) FindBy(id string) (result *model_movie.Movie, err err
 	return
 }
 
-func (cm *ControllerMovie) FindAll(page uint16) (result *controller_interfaces.FindAllResponse[model_movie.Movie], err error) {
+func (cm *ControllerMovie) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[model_movie.Movie], err error) {
+	where, args := Filter(ctx)
 	query := `
-		SELECT id, name, director, duration_in_seconds
+		SELECT id, name, director, year, duration_in_seconds
 		FROM movies
+		` + where + `
 		LIMIT ?
 		OFFSET ?
 	`
-	limit := uint16(10)
+	limit := controller_interfaces.PageSize
 	offset := limit * (page - 1)
-	rows, err := cm.Db.Query(query, limit, offset)
+	rows, err := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
-		return nil, err
+		return nil, cm.sqlError(ctx, "find_all", err)
 	}
+	defer rows.Close()
 	result = &controller_interfaces.FindAllResponse[model_movie.Movie]{}
 	for rows.Next() {
 		var target model_movie.Movie
-		rows.Scan(&target.Id, &target.Name, &target.Director, &target.DurationInSeconds)
+		rows.Scan(&target.Id, &target.Name, &target.Director, &target.Year, &target.DurationInSeconds)
 		err = target.IsValid()
 		if err != nil {
 			continue
 		}
 		result.Registers = append(result.Registers, &target)
 	}
-	result.Total, err = cm.GetTotal()
+	result.Total, err = cm.GetTotal(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -87,20 +114,41 @@ func (cm *ControllerMovie) FindAll(page uint16) (result *controller_interfaces.F
 	return
 }
 
-func (cm *ControllerMovie) GetTotal() (result uint32, err error) {
-	query := `SELECT COUNT(1) FROM movies`
-	rows, err := cm.Db.Query(query)
+func (cm *ControllerMovie) GetTotal(ctx context.Context) (result uint32, err error) {
+	where, args := Filter(ctx)
+	query := `SELECT COUNT(1) FROM movies ` + where
+	rows, err := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return 0, err
+		return 0, cm.sqlError(ctx, "get_total", err)
 	}
+	defer rows.Close()
 	for rows.Next() {
 		rows.Scan(&result)
 	}
 	return
 }
 
-func (cm *ControllerMovie) UpdateBy(id string, m *model_movie.Movie) (result bool, err error) {
-	_, err = cm.FindBy(id)
+// Filter narrows listings and exports to the room from controller_interfaces.WithRoom, as long as it is in the caller's cinema.
+func Filter(ctx context.Context) (where string, args []any) {
+	roomId := controller_interfaces.RoomFrom(ctx)
+	if roomId == "" {
+		return "", nil
+	}
+	where = `WHERE id IN (
+			SELECT room_movies.fk_movie_id
+			FROM room_movies
+			JOIN rooms ON rooms.id = room_movies.fk_room_id
+			WHERE room_movies.fk_room_id = ?`
+	args = []any{roomId}
+	if cinemaId := tenant.CinemaFrom(ctx); cinemaId != "" {
+		where += ` AND rooms.fk_cinema_id = ?`
+		args = append(args, cinemaId)
+	}
+	return where + `)`, args
+}
+
+func (cm *ControllerMovie) UpdateBy(ctx context.Context, id string, m *model_movie.Movie) (result bool, err error) {
+	before, err := cm.FindBy(ctx, id)
 	if err != nil {
 		return false, err
 	}
@@ -109,27 +157,83 @@ func (cm *ControllerMovie) UpdateBy(id string, m *model_movie.Movie) (result boo
 		SET 
 			name = ?,
 			director = ?,
+			year = ?,
 			duration_in_seconds = ?
 		WHERE id = ?; 
 	`
-	_, err = cm.Db.Query(query, &m.Name, &m.Director, &m.DurationInSeconds, id)
+	tx, err := database.BeginTx(ctx, cm.Db)
 	if err != nil {
-		return false, err
+		return false, cm.sqlError(ctx, "update_by", err)
+	}
+	res, err := tx.ExecContext(ctx, query, &m.Name, &m.Director, &m.Year, &m.DurationInSeconds, id)
+	if err == nil {
+		err = database.Affected(res, errors.New("movie not found"))
+	}
+	if err == nil {
+		after := &model_movie.Movie{Id: id, Name: m.Name, Director: m.Director, Year: m.Year, DurationInSeconds: m.DurationInSeconds}
+		err = controller_audit.Record(ctx, tx.Tx, model_audit.ActionUpdate, "movie", id, before, after)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, cm.conflict(ctx, m, cm.sqlError(ctx, "update_by", err))
 	}
 
 	return true, nil
 }
 
-func (cm *ControllerMovie) DeleteBy(id string) (result bool, err error) {
-	_, err = cm.FindBy(id)
+func (cm *ControllerMovie) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	before, err := cm.FindBy(ctx, id)
 	if err != nil {
 		return false, err
 	}
 	query := `DELETE FROM movies WHERE id = ?`
-	_, err = cm.Db.Query(query, &id)
+	tx, err := database.BeginTx(ctx, cm.Db)
 	if err != nil {
-		return false, err
+		return false, cm.sqlError(ctx, "delete_by", err)
+	}
+	res, err := tx.ExecContext(ctx, query, &id)
+	if err == nil {
+		err = database.Affected(res, errors.New("movie not found"))
+	}
+	if err == nil {
+		err = controller_audit.Record(ctx, tx.Tx, model_audit.ActionDelete, "movie", id, before, nil)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, cm.sqlError(ctx, "delete_by", err)
 	}
 
 	return true, nil
 }
+
+// conflict swaps a duplicate-key error for a ConflictError carrying the id of the movie already stored.
+func (cm *ControllerMovie) conflict(ctx context.Context, m *model_movie.Movie, err error) error {
+	if !database.IsDuplicate(err) {
+		return err
+	}
+	query := `SELECT id FROM movies WHERE name = ? AND director = ? AND year = ? LIMIT 1`
+	rows, queryErr := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, &m.Name, &m.Director, &m.Year)
+	if queryErr != nil {
+		return err
+	}
+	defer rows.Close()
+	var existingId string
+	for rows.Next() {
+		rows.Scan(&existingId)
+	}
+	if existingId == "" {
+		return err
+	}
+	return &controller_interfaces.ConflictError{Entity: "movie", ExistingId: existingId}
+}
+
+func (cm *ControllerMovie) sqlError(ctx context.Context, operation string, err error) error {
+	cm.Logger.ErrorContext(ctx, "sql error", "entity", "movie", "operation", operation, "err", err)
+	return err
+}
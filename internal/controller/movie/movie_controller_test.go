@@ -1,55 +1,40 @@
 package controller_movie_test
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
 	"testing"
 
-	"github.com/joho/godotenv"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
 	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
-	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
-	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/seed"
 	"github.com/stretchr/testify/assert"
 )
 
-func instanceMovie() (result *model_movie.Movie) {
-	expected := &model_movie.Movie{
-		Id:                "id",
-		Name:              "name",
-		Director:          "director",
-		DurationInSeconds: 3600,
-	}
-	result, _ = model_movie.NewMovie(expected)
-	return
-}
-
-func instanceDB() (result *sql.DB) {
-	err := godotenv.Load("../../../.env")
-	if err != nil {
-		log.Fatal("Error loading .env file, err: ", err)
-	}
-	result, _ = database.NewDatabaseConnection()
-	result.Query("DELETE FROM room_movies")
-	result.Query("DELETE FROM rooms")
-	result.Query("DELETE FROM movies")
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
 	return
 }
 
 func TestInsert(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
-	movie := instanceMovie()
-	result, err := controllerMovie.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	result, err := controllerMovie.Create(context.Background(), movie)
 	assert.Nil(t, err)
 	assert.Greater(t, len(result), 10)
 }
 
 func TestFindById(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
-	movie := instanceMovie()
-	id, _ := controllerMovie.Create(movie)
-	result, err := controllerMovie.FindBy(id)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := controllerMovie.Create(context.Background(), movie)
+	result, err := controllerMovie.FindBy(context.Background(), id)
 	assert.Nil(t, err)
 	assert.Equal(t, id, result.Id)
 	assert.Equal(t, movie.Name, result.Name)
@@ -59,11 +44,12 @@ func TestFindById(t *testing.T) {
 }
 
 func TestFindAll(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
-	movie := instanceMovie()
-	id, _ := controllerMovie.Create(movie)
-	result, err := controllerMovie.FindAll(1)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := controllerMovie.Create(context.Background(), movie)
+	result, err := controllerMovie.FindAll(context.Background(), 1)
 	assert.Nil(t, err)
 	assert.Equal(t, uint32(1), result.Total)
 	assert.Equal(t, uint16(1), result.Page)
@@ -75,24 +61,37 @@ func TestFindAll(t *testing.T) {
 }
 
 func TestUpdate(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
-	movie := instanceMovie()
-	id, _ := controllerMovie.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := controllerMovie.Create(context.Background(), movie)
 	movie.Name = "new_name"
 	movie.Director = "new_director"
 	movie.DurationInSeconds = 50
-	result, err := controllerMovie.UpdateBy(id, movie)
+	result, err := controllerMovie.UpdateBy(context.Background(), id, movie)
 	assert.Nil(t, err)
 	assert.Equal(t, true, result)
 }
 
 func TestDelete(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
-	movie := instanceMovie()
-	id, _ := controllerMovie.Create(movie)
-	result, err := controllerMovie.DeleteBy(id)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := controllerMovie.Create(context.Background(), movie)
+	result, err := controllerMovie.DeleteBy(context.Background(), id)
 	assert.Nil(t, err)
 	assert.Equal(t, true, result)
 }
+
+func TestFailInsertWithDuplicatedNameDirectorAndYear(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	id, _ := controllerMovie.Create(context.Background(), seed.NewGenerator(seed.DefaultSeed).Movie())
+	_, err := controllerMovie.Create(context.Background(), seed.NewGenerator(seed.DefaultSeed).Movie())
+	var conflict *controller_interfaces.ConflictError
+	assert.True(t, errors.As(err, &conflict))
+	assert.Equal(t, id, conflict.ExistingId)
+}
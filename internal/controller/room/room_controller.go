@@ -1,11 +1,19 @@
 package controller_room
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"log/slog"
+	"strings"
+	"sync"
 
 	"github.com/google/uuid"
+	controller_audit "github.com/rochaeduardo997/irede_golang_dev/internal/controller/audit"
 	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_audit "github.com/rochaeduardo997/irede_golang_dev/internal/model/audit"
 	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
 	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
 )
@@ -13,83 +21,113 @@ import (
 type ControllerRoom struct {
 	Db              *sql.DB
 	MovieController controller_interfaces.IGenericController[model_movie.Movie]
+	Logger          *slog.Logger
 }
 
 func NewControllerRoom(vm *ControllerRoom) (result controller_interfaces.IGenericController[model_room.Room], err error) {
+	if vm.Logger == nil {
+		vm.Logger = slog.Default()
+	}
 	result = vm
 	return
 }
 
-func (cm *ControllerRoom) Create(r *model_room.Room) (result string, err error) {
-	tx, err := cm.Db.Begin()
+func (cm *ControllerRoom) Create(ctx context.Context, r *model_room.Room) (result string, err error) {
+	r.CinemaId, err = tenant.Resolve(ctx, r.CinemaId)
 	if err != nil {
 		return "", err
 	}
+	if r.CinemaId == "" {
+		return "", errors.New("room cinema must be provided")
+	}
+	tx, err := database.BeginTx(ctx, cm.Db)
+	if err != nil {
+		return "", cm.sqlError(ctx, "create", err)
+	}
 	query := `
-		INSERT INTO rooms(id, number, description)
-		VALUES(?,?,?)
+		INSERT INTO rooms(id, fk_cinema_id, number, description)
+		VALUES(?,?,?,?)
 	`
 	r.Id = uuid.NewString()
-	_, err = tx.Query(query, &r.Id, &r.Number, &r.Description)
+	_, err = tx.ExecContext(ctx, query, &r.Id, &r.CinemaId, &r.Number, &r.Description)
 	if err != nil {
 		tx.Rollback()
-		return "", err
+		return "", cm.conflict(ctx, r.CinemaId, r.Number, cm.sqlError(ctx, "create", err))
 	}
 	if len(r.Movies) > 0 {
-		err = cm.InsertRoomMovies(r.Id, r.Movies, tx)
+		err = cm.InsertRoomMovies(ctx, r.Id, r.Movies, tx.Tx)
 		if err != nil {
 			tx.Rollback()
 			return "", err
 		}
 	}
-
-	tx.Commit()
+	err = controller_audit.Record(tenant.WithCinema(ctx, r.CinemaId), tx.Tx, model_audit.ActionCreate, "room", r.Id, nil, r)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		tx.Rollback()
+		return "", cm.sqlError(ctx, "create", err)
+	}
 
 	return r.Id, nil
 }
 
-func (cm *ControllerRoom) InsertRoomMovies(roomId string, ms []*model_movie.Movie, tx *sql.Tx) (err error) {
+func (cm *ControllerRoom) InsertRoomMovies(ctx context.Context, roomId string, ms []*model_movie.Movie, tx *sql.Tx) (err error) {
 	query := `
 		INSERT INTO room_movies(fk_room_id, fk_movie_id)
 		VALUES(?,?)
 	`
 	moviesChan := make(chan *model_movie.Movie)
+	errs := make(chan error, len(ms))
+	var wg sync.WaitGroup
 	for i := 0; i <= 2; i++ {
-		go cm.InsertRoomMoviesThread(query, roomId, tx, moviesChan)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cm.InsertRoomMoviesThread(ctx, query, roomId, tx, moviesChan, errs)
+		}()
 	}
 	for _, movie := range ms {
 		moviesChan <- movie
 	}
-	return nil
+	close(moviesChan)
+	wg.Wait()
+	close(errs)
+	return <-errs
 }
-func (cm *ControllerRoom) InsertRoomMoviesThread(query, roomId string, tx *sql.Tx, moviesChan chan *model_movie.Movie) {
+func (cm *ControllerRoom) InsertRoomMoviesThread(ctx context.Context, query, roomId string, tx *sql.Tx, moviesChan chan *model_movie.Movie, errs chan<- error) {
 	for movie := range moviesChan {
-		_, err := tx.Query(query, &roomId, &movie.Id)
+		_, err := tx.ExecContext(ctx, query, &roomId, &movie.Id)
 		if err != nil {
-			tx.Rollback()
+			errs <- cm.sqlError(ctx, "insert_room_movies", err)
 		}
 	}
 }
 
-func (cm *ControllerRoom) FindBy(id string) (result *model_room.Room, err error) {
+func (cm *ControllerRoom) FindBy(ctx context.Context, id string) (result *model_room.Room, err error) {
+	where, args := scope(ctx, "WHERE id = ?", id)
 	query := `
-		SELECT id, number, description
+		SELECT id, fk_cinema_id, number, description
 		FROM rooms
-		WHERE id = ?
+		` + where + `
 		LIMIT 1
 	`
-	rows, err := cm.Db.Query(query, &id)
+	rows, err := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, cm.sqlError(ctx, "find_by", err)
 	}
+	defer rows.Close()
 	result = &model_room.Room{}
 	for rows.Next() {
-		rows.Scan(&result.Id, &result.Number, &result.Description)
+		rows.Scan(&result.Id, &result.CinemaId, &result.Number, &result.Description)
 	}
 	if result.Id == "" {
 		return nil, errors.New("room not found")
 	}
-	result.Movies = cm.GetAssociatedMoviesBy(result.Id)
+	if !controller_interfaces.MoviesSkipped(ctx) {
+		result.Movies = cm.GetAssociatedMoviesBy(ctx, result.Id)
+	}
 	err = result.IsValid()
 	if err != nil {
 		return nil, err
@@ -98,21 +136,27 @@ func (cm *ControllerRoom) FindBy(id string) (result *model_room.Room, err error)
 	return
 }
 
-func (cm *ControllerRoom) GetAssociatedMoviesBy(roomId string) (result []*model_movie.Movie) {
+func (cm *ControllerRoom) GetAssociatedMoviesBy(ctx context.Context, roomId string) (result []*model_movie.Movie) {
 	query := `
 		SELECT fk_movie_id
 		FROM room_movies
 		WHERE fk_room_id = ?
 	`
-	rows, err := cm.Db.Query(query, &roomId)
+	rows, err := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, &roomId)
 	if err != nil {
+		cm.sqlError(ctx, "get_associated_movies_by", err)
 		return nil
 	}
-	result = []*model_movie.Movie{}
+	defer rows.Close()
+	movieIds := []string{}
 	for rows.Next() {
 		var movieId string
 		rows.Scan(&movieId)
-		movie, err := cm.MovieController.FindBy(movieId)
+		movieIds = append(movieIds, movieId)
+	}
+	result = []*model_movie.Movie{}
+	for _, movieId := range movieIds {
+		movie, err := cm.MovieController.FindBy(ctx, movieId)
 		if err != nil {
 			continue
 		}
@@ -122,31 +166,39 @@ func (cm *ControllerRoom) GetAssociatedMoviesBy(roomId string) (result []*model_
 	return
 }
 
-func (cm *ControllerRoom) FindAll(page uint16) (result *controller_interfaces.FindAllResponse[model_room.Room], err error) {
+func (cm *ControllerRoom) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[model_room.Room], err error) {
+	where, args := scope(ctx, "")
 	query := `
-		SELECT id, number, description
+		SELECT id, fk_cinema_id, number, description
 		FROM rooms
+		` + where + `
 		LIMIT ?
 		OFFSET ?
 	`
-	limit := uint16(10)
+	limit := controller_interfaces.PageSize
 	offset := limit * (page - 1)
-	rows, err := cm.Db.Query(query, limit, offset)
+	rows, err := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
-		return nil, err
+		return nil, cm.sqlError(ctx, "find_all", err)
 	}
+	defer rows.Close()
 	result = &controller_interfaces.FindAllResponse[model_room.Room]{}
 	for rows.Next() {
 		var target model_room.Room
-		rows.Scan(&target.Id, &target.Number, &target.Description)
-		target.Movies = cm.GetAssociatedMoviesBy(target.Id)
+		rows.Scan(&target.Id, &target.CinemaId, &target.Number, &target.Description)
 		err = target.IsValid()
 		if err != nil {
 			continue
 		}
 		result.Registers = append(result.Registers, &target)
 	}
-	result.Total, err = cm.GetTotal()
+	rows.Close()
+	if !controller_interfaces.MoviesSkipped(ctx) {
+		for _, target := range result.Registers {
+			target.Movies = cm.GetAssociatedMoviesBy(ctx, target.Id)
+		}
+	}
+	result.Total, err = cm.GetTotal(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -154,20 +206,22 @@ func (cm *ControllerRoom) FindAll(page uint16) (result *controller_interfaces.Fi
 	return
 }
 
-func (cm *ControllerRoom) GetTotal() (result uint32, err error) {
-	query := `SELECT COUNT(1) FROM rooms`
-	rows, err := cm.Db.Query(query)
+func (cm *ControllerRoom) GetTotal(ctx context.Context) (result uint32, err error) {
+	where, args := scope(ctx, "")
+	query := `SELECT COUNT(1) FROM rooms ` + where
+	rows, err := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return 0, err
+		return 0, cm.sqlError(ctx, "get_total", err)
 	}
+	defer rows.Close()
 	for rows.Next() {
 		rows.Scan(&result)
 	}
 	return
 }
 
-func (cm *ControllerRoom) UpdateBy(id string, m *model_room.Room) (result bool, err error) {
-	_, err = cm.FindBy(id)
+func (cm *ControllerRoom) UpdateBy(ctx context.Context, id string, m *model_room.Room) (result bool, err error) {
+	before, err := cm.FindBy(ctx, id)
 	if err != nil {
 		return false, err
 	}
@@ -178,53 +232,127 @@ func (cm *ControllerRoom) UpdateBy(id string, m *model_room.Room) (result bool,
 			description = ?
 		WHERE id = ?;
 	`
-	tx, err := cm.Db.Begin()
+	tx, err := database.BeginTx(ctx, cm.Db)
 	if err != nil {
-		return false, err
+		return false, cm.sqlError(ctx, "update_by", err)
 	}
-	_, err = tx.Query(updateQuery, &m.Number, &m.Description, id)
+	res, err := tx.ExecContext(ctx, updateQuery, &m.Number, &m.Description, id)
+	if err != nil {
+		tx.Rollback()
+		return false, cm.conflict(ctx, before.CinemaId, m.Number, cm.sqlError(ctx, "update_by", err))
+	}
+	err = database.Affected(res, errors.New("room not found"))
 	if err != nil {
 		tx.Rollback()
 		return false, err
 	}
 	deleteAllRoomMoviesQuery := `DELETE FROM room_movies WHERE fk_room_id = ?`
-	_, err = tx.Query(deleteAllRoomMoviesQuery, &id)
+	_, err = tx.ExecContext(ctx, deleteAllRoomMoviesQuery, &id)
 	if err != nil {
 		tx.Rollback()
-		return false, err
+		return false, cm.sqlError(ctx, "update_by", err)
 	}
-	err = cm.InsertRoomMovies(id, m.Movies, tx)
+	err = cm.InsertRoomMovies(ctx, id, m.Movies, tx.Tx)
 	if err != nil {
 		tx.Rollback()
 		return false, err
 	}
-	tx.Commit()
+	after := &model_room.Room{Id: id, CinemaId: before.CinemaId, Number: m.Number, Description: m.Description, Movies: m.Movies}
+	err = controller_audit.Record(tenant.WithCinema(ctx, before.CinemaId), tx.Tx, model_audit.ActionUpdate, "room", id, before, after)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, cm.sqlError(ctx, "update_by", err)
+	}
 	return true, nil
 }
 
-func (cm *ControllerRoom) DeleteBy(id string) (result bool, err error) {
-	_, err = cm.FindBy(id)
+func (cm *ControllerRoom) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	before, err := cm.FindBy(ctx, id)
 	if err != nil {
 		return false, err
 	}
-	tx, err := cm.Db.Begin()
+	tx, err := database.BeginTx(ctx, cm.Db)
 	if err != nil {
-		return false, err
+		return false, cm.sqlError(ctx, "delete_by", err)
 	}
 	deleteAllRoomMoviesQuery := `DELETE FROM room_movies WHERE fk_room_id = ?`
-	_, err = tx.Query(deleteAllRoomMoviesQuery, &id)
+	_, err = tx.ExecContext(ctx, deleteAllRoomMoviesQuery, &id)
 	if err != nil {
 		tx.Rollback()
-		return false, err
+		return false, cm.sqlError(ctx, "delete_by", err)
 	}
 	query := `DELETE FROM rooms WHERE id = ?`
-	_, err = tx.Query(query, &id)
+	res, err := tx.ExecContext(ctx, query, &id)
+	if err == nil {
+		err = database.Affected(res, errors.New("room not found"))
+	}
+	if err == nil {
+		err = controller_audit.Record(tenant.WithCinema(ctx, before.CinemaId), tx.Tx, model_audit.ActionDelete, "room", id, before, nil)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
 	if err != nil {
 		tx.Rollback()
-		return false, err
+		return false, cm.sqlError(ctx, "delete_by", err)
 	}
 
-	tx.Commit()
-
 	return true, nil
 }
+
+// ShowingMovie narrows rooms to those showing the movie whose id it binds; exports share it with the listings.
+const ShowingMovie = "id IN (SELECT fk_room_id FROM room_movies WHERE fk_movie_id = ?)"
+
+// scope appends the caller's cinema to a WHERE clause so no room query can leak across cinemas, and narrows
+// listings to the movie from controller_interfaces.WithMovie and the rooms from controller_interfaces.WithRooms.
+func scope(ctx context.Context, where string, args ...any) (string, []any) {
+	if cinemaId := tenant.CinemaFrom(ctx); cinemaId != "" {
+		where, args = and(where, "fk_cinema_id = ?"), append(args, cinemaId)
+	}
+	if movieId := controller_interfaces.MovieFrom(ctx); movieId != "" {
+		where, args = and(where, ShowingMovie), append(args, movieId)
+	}
+	if roomIds := controller_interfaces.RoomsFrom(ctx); len(roomIds) > 0 {
+		where = and(where, "id IN (?"+strings.Repeat(", ?", len(roomIds)-1)+")")
+		for _, roomId := range roomIds {
+			args = append(args, roomId)
+		}
+	}
+	return where, args
+}
+
+func and(where, condition string) string {
+	if where == "" {
+		return "WHERE " + condition
+	}
+	return where + " AND " + condition
+}
+
+// conflict swaps a duplicate-key error for a ConflictError carrying the id of the room already using the number.
+func (cm *ControllerRoom) conflict(ctx context.Context, cinemaId string, number uint16, err error) error {
+	if !database.IsDuplicate(err) {
+		return err
+	}
+	query := `SELECT id FROM rooms WHERE fk_cinema_id = ? AND number = ? LIMIT 1`
+	rows, queryErr := database.QuerierFrom(ctx, cm.Db).QueryContext(ctx, query, &cinemaId, &number)
+	if queryErr != nil {
+		return err
+	}
+	defer rows.Close()
+	var existingId string
+	for rows.Next() {
+		rows.Scan(&existingId)
+	}
+	if existingId == "" {
+		return err
+	}
+	return &controller_interfaces.ConflictError{Entity: "room", ExistingId: existingId}
+}
+
+func (cm *ControllerRoom) sqlError(ctx context.Context, operation string, err error) error {
+	cm.Logger.ErrorContext(ctx, "sql error", "entity", "room", "operation", operation, "err", err)
+	return err
+}
@@ -1,72 +1,52 @@
 package controller_room_test
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
 	"testing"
 
-	"github.com/joho/godotenv"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
 	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
 	controller_room "github.com/rochaeduardo997/irede_golang_dev/internal/controller/room"
-	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
-	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/seed"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
 	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
 	"github.com/stretchr/testify/assert"
 )
 
-func instanceMovie() (result *model_movie.Movie) {
-	expected := &model_movie.Movie{
-		Id:                "id",
-		Name:              "name",
-		Director:          "director",
-		DurationInSeconds: 3600,
-	}
-	result, _ = model_movie.NewMovie(expected)
-	return
-}
-
-func instanceRoom() (result *model_room.Room) {
-	movie := instanceMovie()
-	result, _ = model_room.NewRoom(&model_room.Room{
-		Id:          "id",
-		Number:      200,
-		Description: "description",
-		Movies:      []*model_movie.Movie{movie},
-	})
-	return
-}
+const cinemaId = "cinema-id"
 
-func instanceDB() (result *sql.DB) {
-	err := godotenv.Load("../../../.env")
-	if err != nil {
-		log.Fatal("Error loading .env file, err: ", err)
-	}
-	result, _ = database.NewDatabaseConnection()
-	result.Query("DELETE FROM room_movies")
-	result.Query("DELETE FROM rooms")
-	result.Query("DELETE FROM movies")
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	result.Exec("INSERT INTO cinemas(id, name) VALUES(?, ?)", cinemaId, "cinema")
 	return
 }
 
 func TestInsert(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
 	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
-	room := instanceRoom()
-	controllerMovie.Create(room.Movies[0])
-	result, err := controllerRoom.Create(room)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	room := g.Room(cinemaId, g.Movie())
+	controllerMovie.Create(context.Background(), room.Movies[0])
+	result, err := controllerRoom.Create(context.Background(), room)
 	assert.Nil(t, err)
 	assert.Greater(t, len(result), 10)
 }
 
 func TestFindById(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
 	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
-	room := instanceRoom()
-	controllerMovie.Create(room.Movies[0])
-	id, _ := controllerRoom.Create(room)
-	result, err := controllerRoom.FindBy(id)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	room := g.Room(cinemaId, g.Movie())
+	controllerMovie.Create(context.Background(), room.Movies[0])
+	id, _ := controllerRoom.Create(context.Background(), room)
+	result, err := controllerRoom.FindBy(context.Background(), id)
 	assert.Nil(t, err)
 	assert.Equal(t, id, result.Id)
 	assert.Equal(t, room.Number, result.Number)
@@ -75,13 +55,15 @@ func TestFindById(t *testing.T) {
 }
 
 func TestFindAll(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
 	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
-	room := instanceRoom()
-	controllerMovie.Create(room.Movies[0])
-	id, _ := controllerRoom.Create(room)
-	result, err := controllerRoom.FindAll(1)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	room := g.Room(cinemaId, g.Movie())
+	controllerMovie.Create(context.Background(), room.Movies[0])
+	id, _ := controllerRoom.Create(context.Background(), room)
+	result, err := controllerRoom.FindAll(context.Background(), 1)
 	assert.Nil(t, err)
 	assert.Equal(t, uint32(1), result.Total)
 	assert.Equal(t, uint16(1), result.Page)
@@ -92,27 +74,131 @@ func TestFindAll(t *testing.T) {
 }
 
 func TestUpdate(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
 	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
-	room := instanceRoom()
-	controllerMovie.Create(room.Movies[0])
-	id, _ := controllerRoom.Create(room)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	room := g.Room(cinemaId, g.Movie())
+	controllerMovie.Create(context.Background(), room.Movies[0])
+	id, _ := controllerRoom.Create(context.Background(), room)
 	room.Number = 300
 	room.Description = "new_description"
-	result, err := controllerRoom.UpdateBy(id, room)
+	result, err := controllerRoom.UpdateBy(context.Background(), id, room)
 	assert.Nil(t, err)
 	assert.Equal(t, true, result)
 }
 
 func TestDelete(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	db := instanceDB(t)
 	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
 	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
-	room := instanceRoom()
-	controllerMovie.Create(room.Movies[0])
-	controllerRoom.Create(room)
-	result, err := controllerRoom.DeleteBy(room.Id)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	room := g.Room(cinemaId, g.Movie())
+	controllerMovie.Create(context.Background(), room.Movies[0])
+	controllerRoom.Create(context.Background(), room)
+	result, err := controllerRoom.DeleteBy(context.Background(), room.Id)
 	assert.Nil(t, err)
 	assert.Equal(t, true, result)
 }
+
+func TestFindAllScopedToCinema(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	db.Exec("INSERT INTO cinemas(id, name) VALUES(?, ?)", "other-cinema-id", "other cinema")
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
+	id, _ := controllerRoom.Create(context.Background(), &model_room.Room{CinemaId: cinemaId, Number: 1, Description: "description"})
+	otherId, _ := controllerRoom.Create(context.Background(), &model_room.Room{CinemaId: "other-cinema-id", Number: 1, Description: "description"})
+	ctx := tenant.WithCinema(context.Background(), cinemaId)
+
+	result, err := controllerRoom.FindAll(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), result.Total)
+	assert.Equal(t, id, result.Registers[0].Id)
+
+	_, err = controllerRoom.FindBy(ctx, otherId)
+	assert.EqualError(t, err, "room not found")
+	_, err = controllerRoom.DeleteBy(ctx, otherId)
+	assert.EqualError(t, err, "room not found")
+	_, err = controllerRoom.Create(ctx, &model_room.Room{CinemaId: "other-cinema-id", Number: 2, Description: "description"})
+	assert.ErrorIs(t, err, tenant.ErrOtherCinema)
+}
+
+func TestFindAllFilteredByMovieAndRoom(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	db.Exec("INSERT INTO cinemas(id, name) VALUES(?, ?)", "other-cinema-id", "other cinema")
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
+	g := seed.NewGenerator(seed.DefaultSeed)
+	shown, other := g.Movie(), g.Movie()
+	shown.Id, _ = controllerMovie.Create(context.Background(), shown)
+	other.Id, _ = controllerMovie.Create(context.Background(), other)
+	room := g.Room(cinemaId, shown)
+	controllerRoom.Create(context.Background(), room)
+	controllerRoom.Create(context.Background(), g.Room(cinemaId, other))
+	otherCinemaRoom := g.Room("other-cinema-id", shown)
+	controllerRoom.Create(context.Background(), otherCinemaRoom)
+
+	rooms, err := controllerRoom.FindAll(controller_interfaces.WithMovie(tenant.WithCinema(context.Background(), cinemaId), shown.Id), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), rooms.Total)
+	assert.Equal(t, room.Id, rooms.Registers[0].Id)
+
+	movies, err := controllerMovie.FindAll(controller_interfaces.WithRoom(context.Background(), room.Id), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), movies.Total)
+	assert.Equal(t, shown.Id, movies.Registers[0].Id)
+
+	movies, err = controllerMovie.FindAll(controller_interfaces.WithRoom(tenant.WithCinema(context.Background(), cinemaId), otherCinemaRoom.Id), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), movies.Total)
+	assert.Empty(t, movies.Registers)
+}
+
+func TestFindWithoutMoviesSkipsAssociation(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movie.Id, _ = controllerMovie.Create(context.Background(), movie)
+	room := g.Room(cinemaId, movie)
+	controllerRoom.Create(context.Background(), room)
+
+	ctx := controller_interfaces.WithoutMovies(context.Background())
+	found, err := controllerRoom.FindBy(ctx, room.Id)
+	assert.Nil(t, err)
+	assert.Empty(t, found.Movies)
+	rooms, err := controllerRoom.FindAll(ctx, 1)
+	assert.Nil(t, err)
+	assert.Empty(t, rooms.Registers[0].Movies)
+
+	found, _ = controllerRoom.FindBy(context.Background(), room.Id)
+	assert.Len(t, found.Movies, 1)
+}
+
+func TestFailInsertWithDuplicatedNumberInCinema(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
+	controllerRoom.Create(context.Background(), &model_room.Room{CinemaId: cinemaId, Number: 1, Description: "description"})
+	_, err := controllerRoom.Create(context.Background(), &model_room.Room{CinemaId: cinemaId, Number: 1, Description: "description"})
+	assert.NotNil(t, err)
+}
+
+func TestFailInsertWithDuplicatedNumberReturnsConflict(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerMovie, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	controllerRoom, _ := controller_room.NewControllerRoom(&controller_room.ControllerRoom{Db: db, MovieController: controllerMovie})
+	id, _ := controllerRoom.Create(context.Background(), &model_room.Room{CinemaId: cinemaId, Number: 1, Description: "description"})
+	_, err := controllerRoom.Create(context.Background(), &model_room.Room{CinemaId: cinemaId, Number: 1, Description: "description"})
+	var conflict *controller_interfaces.ConflictError
+	assert.True(t, errors.As(err, &conflict))
+	assert.Equal(t, id, conflict.ExistingId)
+}
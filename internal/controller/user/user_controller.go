@@ -0,0 +1,230 @@
+package controller_user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_user "github.com/rochaeduardo997/irede_golang_dev/internal/model/user"
+)
+
+type ControllerUser struct {
+	Db     *sql.DB
+	Logger *slog.Logger
+}
+
+func NewControllerUser(cu *ControllerUser) (result controller_interfaces.IUserController, err error) {
+	if cu.Logger == nil {
+		cu.Logger = slog.Default()
+	}
+	result = cu
+	return
+}
+
+func (cu *ControllerUser) Create(ctx context.Context, u *model_user.User) (result string, err error) {
+	if u.Password != "" {
+		err = u.SetPassword(u.Password)
+		if err != nil {
+			return "", err
+		}
+	}
+	query := `
+		INSERT INTO users(id, username, password_hash, roles, cinema_id, disabled)
+		VALUES(?,?,?,?,?,?)
+	`
+	u.Id = uuid.NewString()
+	_, err = cu.Db.ExecContext(ctx, query, &u.Id, &u.Username, &u.PasswordHash, strings.Join(u.Roles, ","), nullable(u.CinemaId), &u.Disabled)
+	if err != nil {
+		return "", cu.sqlError(ctx, "create", err)
+	}
+
+	return u.Id, nil
+}
+
+func (cu *ControllerUser) FindBy(ctx context.Context, id string) (result *model_user.User, err error) {
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `
+		SELECT id, username, password_hash, roles, cinema_id, disabled
+		FROM users
+		` + where + `
+		LIMIT 1
+	`
+	return cu.findOne(ctx, "find_by", query, args...)
+}
+
+func (cu *ControllerUser) FindByUsername(ctx context.Context, username string) (result *model_user.User, err error) {
+	where, args := tenant.Where(ctx, "WHERE username = ?", "cinema_id", username)
+	query := `
+		SELECT id, username, password_hash, roles, cinema_id, disabled
+		FROM users
+		` + where + `
+		LIMIT 1
+	`
+	return cu.findOne(ctx, "find_by_username", query, args...)
+}
+
+func (cu *ControllerUser) findOne(ctx context.Context, operation, query string, args ...any) (result *model_user.User, err error) {
+	rows, err := cu.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, cu.sqlError(ctx, operation, err)
+	}
+	defer rows.Close()
+	result = &model_user.User{}
+	for rows.Next() {
+		scan(rows, result)
+	}
+	if result.Id == "" {
+		return nil, errors.New("user not found")
+	}
+	err = result.IsValid()
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+func (cu *ControllerUser) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[model_user.User], err error) {
+	where, args := tenant.Where(ctx, "", "cinema_id")
+	query := `
+		SELECT id, username, password_hash, roles, cinema_id, disabled
+		FROM users
+		` + where + `
+		LIMIT ?
+		OFFSET ?
+	`
+	limit := controller_interfaces.PageSize
+	offset := limit * (page - 1)
+	rows, err := cu.Db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, cu.sqlError(ctx, "find_all", err)
+	}
+	defer rows.Close()
+	result = &controller_interfaces.FindAllResponse[model_user.User]{}
+	for rows.Next() {
+		var target model_user.User
+		scan(rows, &target)
+		err = target.IsValid()
+		if err != nil {
+			continue
+		}
+		result.Registers = append(result.Registers, &target)
+	}
+	result.Total, err = cu.GetTotal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Page = page
+	return
+}
+
+func (cu *ControllerUser) GetTotal(ctx context.Context) (result uint32, err error) {
+	where, args := tenant.Where(ctx, "", "cinema_id")
+	query := `SELECT COUNT(1) FROM users ` + where
+	rows, err := cu.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, cu.sqlError(ctx, "get_total", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&result)
+	}
+	return
+}
+
+func (cu *ControllerUser) UpdateBy(ctx context.Context, id string, u *model_user.User) (result bool, err error) {
+	_, err = cu.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `
+		UPDATE users
+		SET
+			username = ?,
+			roles = ?,
+			cinema_id = ?
+		` + where
+	_, err = cu.Db.ExecContext(ctx, query, append([]any{&u.Username, strings.Join(u.Roles, ","), nullable(u.CinemaId)}, args...)...)
+	if err != nil {
+		return false, cu.sqlError(ctx, "update_by", err)
+	}
+
+	return true, nil
+}
+
+func (cu *ControllerUser) ChangePassword(ctx context.Context, id, password string) (result bool, err error) {
+	user, err := cu.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	err = user.SetPassword(password)
+	if err != nil {
+		return false, err
+	}
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `UPDATE users SET password_hash = ? ` + where
+	_, err = cu.Db.ExecContext(ctx, query, append([]any{&user.PasswordHash}, args...)...)
+	if err != nil {
+		return false, cu.sqlError(ctx, "change_password", err)
+	}
+
+	return true, nil
+}
+
+func (cu *ControllerUser) SetDisabled(ctx context.Context, id string, disabled bool) (result bool, err error) {
+	_, err = cu.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `UPDATE users SET disabled = ? ` + where
+	_, err = cu.Db.ExecContext(ctx, query, append([]any{&disabled}, args...)...)
+	if err != nil {
+		return false, cu.sqlError(ctx, "set_disabled", err)
+	}
+
+	return true, nil
+}
+
+func (cu *ControllerUser) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	_, err = cu.FindBy(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	where, args := tenant.Where(ctx, "WHERE id = ?", "cinema_id", id)
+	query := `DELETE FROM users ` + where
+	_, err = cu.Db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, cu.sqlError(ctx, "delete_by", err)
+	}
+
+	return true, nil
+}
+
+func scan(rows *sql.Rows, u *model_user.User) {
+	var roles string
+	var cinemaId sql.NullString
+	rows.Scan(&u.Id, &u.Username, &u.PasswordHash, &roles, &cinemaId, &u.Disabled)
+	u.CinemaId = cinemaId.String
+	u.Roles = nil
+	for _, role := range strings.Split(roles, ",") {
+		if role != "" {
+			u.Roles = append(u.Roles, role)
+		}
+	}
+}
+
+func nullable(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func (cu *ControllerUser) sqlError(ctx context.Context, operation string, err error) error {
+	cu.Logger.ErrorContext(ctx, "sql error", "entity", "user", "operation", operation, "err", err)
+	return err
+}
@@ -0,0 +1,182 @@
+package controller_user_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	controller_user "github.com/rochaeduardo997/irede_golang_dev/internal/controller/user"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_user "github.com/rochaeduardo997/irede_golang_dev/internal/model/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceUser() (result *model_user.User) {
+	result, _ = model_user.NewUser(&model_user.User{
+		Id:       "id",
+		Username: "username",
+		Password: "password",
+		Roles:    []string{"programmer"},
+	})
+	return
+}
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	return
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	result, err := controllerUser.Create(context.Background(), user)
+	assert.Nil(t, err)
+	assert.Greater(t, len(result), 10)
+}
+
+func TestFindById(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	id, _ := controllerUser.Create(context.Background(), user)
+	result, err := controllerUser.FindBy(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, id, result.Id)
+	assert.Equal(t, user.Username, result.Username)
+	assert.Equal(t, user.Roles, result.Roles)
+	assert.Equal(t, false, result.Disabled)
+	assert.True(t, result.CheckPassword("password"))
+}
+
+func TestFindByUsername(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	id, _ := controllerUser.Create(context.Background(), user)
+	result, err := controllerUser.FindByUsername(context.Background(), user.Username)
+	assert.Nil(t, err)
+	assert.Equal(t, id, result.Id)
+}
+
+func TestFindAll(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	id, _ := controllerUser.Create(context.Background(), user)
+	result, err := controllerUser.FindAll(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), result.Total)
+	assert.Equal(t, uint16(1), result.Page)
+	assert.Equal(t, id, result.Registers[0].Id)
+	assert.Equal(t, user.Username, result.Registers[0].Username)
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	id, _ := controllerUser.Create(context.Background(), user)
+	user.Username = "new_username"
+	user.Roles = []string{"box_office", "programmer"}
+	result, err := controllerUser.UpdateBy(context.Background(), id, user)
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+	updated, _ := controllerUser.FindBy(context.Background(), id)
+	assert.Equal(t, "new_username", updated.Username)
+	assert.Equal(t, []string{"box_office", "programmer"}, updated.Roles)
+}
+
+func TestChangePassword(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	id, _ := controllerUser.Create(context.Background(), user)
+	result, err := controllerUser.ChangePassword(context.Background(), id, "new_password")
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+	updated, _ := controllerUser.FindBy(context.Background(), id)
+	assert.True(t, updated.CheckPassword("new_password"))
+	assert.False(t, updated.CheckPassword("password"))
+}
+
+func TestSetDisabled(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	id, _ := controllerUser.Create(context.Background(), user)
+	result, err := controllerUser.SetDisabled(context.Background(), id, true)
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+	updated, _ := controllerUser.FindBy(context.Background(), id)
+	assert.Equal(t, true, updated.Disabled)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	id, _ := controllerUser.Create(context.Background(), user)
+	result, err := controllerUser.DeleteBy(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+}
+
+func TestFailChangePasswordWithShortPassword(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	user := instanceUser()
+	id, _ := controllerUser.Create(context.Background(), user)
+	result, err := controllerUser.ChangePassword(context.Background(), id, "short")
+	assert.Equal(t, false, result)
+	assert.EqualError(t, err, "user password must have at least 8 characters")
+}
+
+func TestScopedToCinema(t *testing.T) {
+	t.Parallel()
+	db := instanceDB(t)
+	db.Exec("INSERT INTO cinemas(id, name) VALUES('cinema-a', 'a'), ('cinema-b', 'b')")
+	controllerUser, _ := controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	own := instanceUser()
+	own.CinemaId = "cinema-a"
+	controllerUser.Create(context.Background(), own)
+	other := instanceUser()
+	other.Username, other.CinemaId = "other", "cinema-b"
+	controllerUser.Create(context.Background(), other)
+	admin := instanceUser()
+	admin.Username = "admin"
+	controllerUser.Create(context.Background(), admin)
+
+	ctx := tenant.WithCinema(context.Background(), "cinema-a")
+	all, err := controllerUser.FindAll(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), all.Total)
+	assert.Equal(t, own.Id, all.Registers[0].Id)
+	_, err = controllerUser.FindByUsername(ctx, "admin")
+	assert.EqualError(t, err, "user not found")
+	for _, id := range []string{other.Id, admin.Id} {
+		_, err = controllerUser.FindBy(ctx, id)
+		assert.EqualError(t, err, "user not found")
+		_, err = controllerUser.UpdateBy(ctx, id, own)
+		assert.EqualError(t, err, "user not found")
+		_, err = controllerUser.ChangePassword(ctx, id, "new_password")
+		assert.EqualError(t, err, "user not found")
+		_, err = controllerUser.SetDisabled(ctx, id, true)
+		assert.EqualError(t, err, "user not found")
+		_, err = controllerUser.DeleteBy(ctx, id)
+		assert.EqualError(t, err, "user not found")
+	}
+	untouched, _ := controllerUser.FindBy(context.Background(), admin.Id)
+	assert.True(t, untouched.CheckPassword("password"))
+	assert.False(t, untouched.Disabled)
+}
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+type Claims struct {
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Scope       string   `json:"scope,omitempty"`
+	Cinema      string   `json:"cinema,omitempty"`
+	jwt.RegisteredClaims
+}
+
+const APIKeyHeader = "X-API-Key"
+
+type Principal struct {
+	Subject     string
+	Roles       []string
+	Permissions []string
+	RoomIds     []string
+	CinemaId    string
+}
+
+type principalKey struct{}
+
+type Authenticator struct {
+	Issuer     string
+	Audience   string
+	HMACSecret []byte
+	RSAKeys    map[string]*rsa.PublicKey
+	TokenTTL   time.Duration
+	Validate   func(ctx context.Context, principal *Principal) error
+	APIKeys    func(ctx context.Context, key string) (*Principal, error)
+}
+
+func NewAuthenticator(a *Authenticator) (result *Authenticator, err error) {
+	if len(a.HMACSecret) == 0 && len(a.RSAKeys) == 0 {
+		return nil, errors.New("authenticator needs an HMAC secret or a JWKS")
+	}
+	if a.TokenTTL == 0 {
+		a.TokenTTL = time.Hour
+	}
+	result = a
+	return
+}
+
+func NewAuthenticatorFromEnv() (result *Authenticator, err error) {
+	a := &Authenticator{
+		Issuer:     os.Getenv("JWT_ISSUER"),
+		Audience:   os.Getenv("JWT_AUDIENCE"),
+		HMACSecret: []byte(os.Getenv("JWT_HS256_SECRET")),
+	}
+	if ttl := os.Getenv("JWT_TTL"); ttl != "" {
+		a.TokenTTL, err = time.ParseDuration(ttl)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if path := os.Getenv("JWT_JWKS_FILE"); path != "" {
+		a.RSAKeys, err = LoadJWKS(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewAuthenticator(a)
+}
+
+func (a *Authenticator) Parse(token string) (result *Principal, err error) {
+	opts := []jwt.ParserOption{jwt.WithValidMethods(a.methods()), jwt.WithExpirationRequired()}
+	if a.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.Issuer))
+	}
+	if a.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.Audience))
+	}
+	claims := &Claims{}
+	_, err = jwt.ParseWithClaims(token, claims, a.key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	result = &Principal{Subject: claims.Subject, Roles: claims.Roles, Permissions: claims.Permissions, CinemaId: claims.Cinema}
+	if claims.Scope != "" {
+		result.Permissions = append(result.Permissions, strings.Fields(claims.Scope)...)
+	}
+	return
+}
+
+func (a *Authenticator) Issue(principal *Principal) (result string, expiresAt time.Time, err error) {
+	if len(a.HMACSecret) == 0 {
+		return "", time.Time{}, errors.New("token issuing needs an HMAC secret")
+	}
+	now := time.Now()
+	expiresAt = now.Add(a.TokenTTL)
+	claims := &Claims{
+		Roles:       principal.Roles,
+		Permissions: principal.Permissions,
+		Cinema:      principal.CinemaId,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   principal.Subject,
+			Issuer:    a.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	if a.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{a.Audience}
+	}
+	result, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.HMACSecret)
+	return
+}
+
+func (a *Authenticator) methods() (result []string) {
+	if len(a.HMACSecret) > 0 {
+		result = append(result, jwt.SigningMethodHS256.Alg())
+	}
+	if len(a.RSAKeys) > 0 {
+		result = append(result, jwt.SigningMethodRS256.Alg())
+	}
+	return
+}
+
+func (a *Authenticator) key(token *jwt.Token) (any, error) {
+	if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+		return a.HMACSecret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := a.RSAKeys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(a.RSAKeys) == 1 {
+		for _, key := range a.RSAKeys {
+			return key, nil
+		}
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+func (a *Authenticator) Middleware() http_adapter.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get(APIKeyHeader); key != "" && a.APIKeys != nil {
+				principal, err := a.APIKeys(r.Context(), key)
+				if err != nil {
+					unauthorized(w, "invalid api key")
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+				return
+			}
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, "missing bearer token")
+				return
+			}
+			principal, err := a.Parse(token)
+			if err != nil {
+				unauthorized(w, "invalid token")
+				return
+			}
+			if a.Validate != nil {
+				err = a.Validate(r.Context(), principal)
+				if err != nil {
+					unauthorized(w, err.Error())
+					return
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (result string, ok bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	http.Error(w, message, http.StatusUnauthorized)
+}
+
+// AllowsRoom reports whether the principal may touch the room; an empty RoomIds means every room.
+func (p *Principal) AllowsRoom(id string) bool {
+	if p == nil || len(p.RoomIds) == 0 {
+		return true
+	}
+	for _, roomId := range p.RoomIds {
+		if roomId == id {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimitKey identifies the caller for rate limiting; API key subjects are already prefixed with "apikey:".
+func RateLimitKey(r *http.Request) string {
+	principal := PrincipalFrom(r.Context())
+	if principal == nil {
+		return ""
+	}
+	if strings.HasPrefix(principal.Subject, "apikey:") {
+		return principal.Subject
+	}
+	return "user:" + principal.Subject
+}
+
+// WithPrincipal also scopes ctx to the principal's cinema, if any.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	if p != nil {
+		ctx = tenant.WithCinema(ctx, p.CinemaId)
+	}
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFrom(ctx context.Context) (result *Principal) {
+	result, _ = ctx.Value(principalKey{}).(*Principal)
+	return
+}
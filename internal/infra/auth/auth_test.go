@@ -0,0 +1,229 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+var secret = []byte("secret")
+
+func instanceClaims() *auth.Claims {
+	return &auth.Claims{
+		Roles: []string{"programmer"},
+		Scope: "movies:read movies:write",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-id",
+			Issuer:    "issuer",
+			Audience:  jwt.ClaimStrings{"audience"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func signHS256(claims *auth.Claims) string {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	return token
+}
+
+func instanceAuthenticator() *auth.Authenticator {
+	result, _ := auth.NewAuthenticator(&auth.Authenticator{Issuer: "issuer", Audience: "audience", HMACSecret: secret})
+	return result
+}
+
+func TestParseHS256(t *testing.T) {
+	principal, err := instanceAuthenticator().Parse(signHS256(instanceClaims()))
+	assert.Nil(t, err)
+	assert.Equal(t, "user-id", principal.Subject)
+	assert.Equal(t, []string{"programmer"}, principal.Roles)
+	assert.Equal(t, []string{"movies:read", "movies:write"}, principal.Permissions)
+}
+
+func TestFailParseWithWrongAudience(t *testing.T) {
+	claims := instanceClaims()
+	claims.Audience = jwt.ClaimStrings{"other"}
+	_, err := instanceAuthenticator().Parse(signHS256(claims))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+}
+
+func TestFailParseWithWrongIssuer(t *testing.T) {
+	claims := instanceClaims()
+	claims.Issuer = "other"
+	_, err := instanceAuthenticator().Parse(signHS256(claims))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+}
+
+func TestFailParseExpiredToken(t *testing.T) {
+	claims := instanceClaims()
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	_, err := instanceAuthenticator().Parse(signHS256(claims))
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+func TestParseRS256WithJWKSFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	jwks := auth.JWKS{Keys: []auth.JWK{{
+		Kid: "key-1",
+		Kty: "RSA",
+		Alg: "RS256",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+	}}}
+	content, _ := json.Marshal(jwks)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.Nil(t, os.WriteFile(path, content, 0o600))
+
+	t.Setenv("JWT_ISSUER", "issuer")
+	t.Setenv("JWT_AUDIENCE", "audience")
+	t.Setenv("JWT_HS256_SECRET", "")
+	t.Setenv("JWT_JWKS_FILE", path)
+	authenticator, err := auth.NewAuthenticatorFromEnv()
+	assert.Nil(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, instanceClaims())
+	token.Header["kid"] = "key-1"
+	signed, _ := token.SignedString(key)
+	principal, err := authenticator.Parse(signed)
+	assert.Nil(t, err)
+	assert.Equal(t, "user-id", principal.Subject)
+
+	_, err = authenticator.Parse(signHS256(instanceClaims()))
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
+
+func TestMiddleware(t *testing.T) {
+	authenticator := instanceAuthenticator()
+	httpAdapter, handler := http_adapter.NewServeMux()
+	api := httpAdapter.Group("/api/v1", authenticator.Middleware())
+	api.AddRoute("get", "/me", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(auth.PrincipalFrom(r.Context()).Subject))
+	})
+	httpAdapter.AddRoute("get", "/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/me", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer realm="api"`, rec.Header().Get("WWW-Authenticate"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(instanceClaims()))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "user-id", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestIssue(t *testing.T) {
+	authenticator := instanceAuthenticator()
+	token, expiresAt, err := authenticator.Issue(&auth.Principal{Subject: "user-id", Roles: []string{"admin"}})
+	assert.Nil(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+	principal, err := authenticator.Parse(token)
+	assert.Nil(t, err)
+	assert.Equal(t, "user-id", principal.Subject)
+	assert.Equal(t, []string{"admin"}, principal.Roles)
+}
+
+func TestMiddlewareValidate(t *testing.T) {
+	authenticator := instanceAuthenticator()
+	authenticator.Validate = func(ctx context.Context, principal *auth.Principal) error {
+		return errors.New("user disabled")
+	}
+	httpAdapter, handler := http_adapter.NewServeMux()
+	httpAdapter.AddRoute("get", "/me", func(w http.ResponseWriter, r *http.Request) {}, authenticator.Middleware())
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(instanceClaims()))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestMiddlewareAPIKey(t *testing.T) {
+	authenticator := instanceAuthenticator()
+	authenticator.APIKeys = func(ctx context.Context, key string) (*auth.Principal, error) {
+		if key != "valid-key" {
+			return nil, errors.New("invalid api key")
+		}
+		return &auth.Principal{Subject: "apikey:id", Permissions: []string{"rooms:read"}}, nil
+	}
+	httpAdapter, handler := http_adapter.NewServeMux()
+	httpAdapter.AddRoute("get", "/me", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(auth.PrincipalFrom(r.Context()).Subject))
+	}, authenticator.Middleware())
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(auth.APIKeyHeader, "valid-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "apikey:id", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(auth.APIKeyHeader, "invalid-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(instanceClaims()))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "user-id", rec.Body.String())
+}
+
+func TestPrincipalAllowsRoom(t *testing.T) {
+	var nobody *auth.Principal
+	assert.True(t, nobody.AllowsRoom("room-a"))
+	assert.True(t, (&auth.Principal{}).AllowsRoom("room-a"))
+	scoped := &auth.Principal{RoomIds: []string{"room-a"}}
+	assert.True(t, scoped.AllowsRoom("room-a"))
+	assert.False(t, scoped.AllowsRoom("room-b"))
+}
+
+func TestRateLimitKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Equal(t, "", auth.RateLimitKey(req))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Subject: "user-id"}))
+	assert.Equal(t, "user:user-id", auth.RateLimitKey(req))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Subject: "apikey:key-id"}))
+	assert.Equal(t, "apikey:key-id", auth.RateLimitKey(req))
+}
+
+func TestIssueCarriesCinema(t *testing.T) {
+	authenticator := instanceAuthenticator()
+	token, _, _ := authenticator.Issue(&auth.Principal{Subject: "user-id", Roles: []string{"box_office"}, CinemaId: "cinema-id"})
+	principal, err := authenticator.Parse(token)
+	assert.Nil(t, err)
+	assert.Equal(t, "cinema-id", principal.CinemaId)
+	ctx := auth.WithPrincipal(context.Background(), principal)
+	assert.Equal(t, "cinema-id", tenant.CinemaFrom(ctx))
+}
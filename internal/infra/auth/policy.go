@@ -0,0 +1,112 @@
+package auth
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+const (
+	MoviesRead   = "movies:read"
+	MoviesWrite  = "movies:write"
+	RoomsRead    = "rooms:read"
+	RoomsWrite   = "rooms:write"
+	CinemasRead  = "cinemas:read"
+	CinemasWrite = "cinemas:write"
+	UsersRead    = "users:read"
+	UsersWrite   = "users:write"
+
+	APIKeysRead  = "apikeys:read"
+	APIKeysWrite = "apikeys:write"
+
+	AuditRead = "audit:read"
+)
+
+//go:embed default_policy.json
+var defaultPolicy []byte
+
+type Policy struct {
+	Roles map[string][]string `json:"roles"`
+}
+
+func NewPolicy(content []byte) (result *Policy, err error) {
+	result = &Policy{}
+	err = json.Unmarshal(content, result)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func LoadPolicy(path string) (result *Policy, err error) {
+	if path == "" {
+		return NewPolicy(defaultPolicy)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPolicy(content)
+}
+
+// RolePermissions lists what the policy grants to the role; a nil policy grants nothing by role.
+func (p *Policy) RolePermissions(role string) []string {
+	if p == nil {
+		return nil
+	}
+	return p.Roles[role]
+}
+
+func (p *Policy) Allows(principal *Principal, permission string) bool {
+	if p == nil {
+		return true
+	}
+	if principal == nil {
+		return false
+	}
+	if grants(principal.Permissions, permission) {
+		return true
+	}
+	for _, role := range principal.Roles {
+		if grants(p.Roles[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func grants(granted []string, permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, g := range granted {
+		if g == "*" || g == permission || g == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Require is a no-op on a nil policy so views can be mounted without RBAC.
+func (p *Policy) Require(permission string) http_adapter.Middleware {
+	return func(next http.Handler) http.Handler {
+		if p == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := PrincipalFrom(r.Context())
+			if principal == nil {
+				unauthorized(w, "missing bearer token")
+				return
+			}
+			if !p.Allows(principal, permission) {
+				http.Error(w, fmt.Sprintf("missing permission %s", permission), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	policy, err := auth.LoadPolicy("")
+	assert.Nil(t, err)
+	boxOffice := &auth.Principal{Subject: "cashier", Roles: []string{"box_office"}}
+	programmer := &auth.Principal{Subject: "programmer", Roles: []string{"programmer"}}
+	admin := &auth.Principal{Subject: "admin", Roles: []string{"admin"}}
+
+	assert.True(t, policy.Allows(boxOffice, auth.RoomsRead))
+	assert.False(t, policy.Allows(boxOffice, auth.RoomsWrite))
+	assert.True(t, policy.Allows(programmer, auth.MoviesWrite))
+	assert.True(t, policy.Allows(admin, auth.RoomsWrite))
+	assert.False(t, policy.Allows(nil, auth.RoomsRead))
+}
+
+func TestPolicyFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	os.WriteFile(path, []byte(`{"roles": {"curator": ["movies:*"]}}`), 0o600)
+	policy, err := auth.LoadPolicy(path)
+	assert.Nil(t, err)
+
+	curator := &auth.Principal{Roles: []string{"curator"}}
+	assert.True(t, policy.Allows(curator, auth.MoviesWrite))
+	assert.False(t, policy.Allows(curator, auth.RoomsRead))
+	assert.True(t, policy.Allows(&auth.Principal{Permissions: []string{auth.RoomsRead}}, auth.RoomsRead))
+}
+
+func TestRequire(t *testing.T) {
+	policy, _ := auth.LoadPolicy("")
+	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter.AddRoute("post", "/rooms", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}, withPrincipal(&auth.Principal{Roles: []string{"box_office"}}), policy.Require(auth.RoomsWrite))
+	httpAdapter.AddRoute("get", "/rooms", func(w http.ResponseWriter, r *http.Request) {},
+		withPrincipal(&auth.Principal{Roles: []string{"box_office"}}), policy.Require(auth.RoomsRead))
+	httpAdapter.AddRoute("get", "/anonymous", func(w http.ResponseWriter, r *http.Request) {}, policy.Require(auth.RoomsRead))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rooms", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, "missing permission rooms:write\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/anonymous", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequireWithoutPolicy(t *testing.T) {
+	var policy *auth.Policy
+	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter.AddRoute("get", "/rooms", func(w http.ResponseWriter, r *http.Request) {}, policy.Require(auth.RoomsRead))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func withPrincipal(p *auth.Principal) http_adapter.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+		})
+	}
+}
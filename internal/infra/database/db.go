@@ -2,11 +2,14 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/XSAM/otelsql"
 	"github.com/go-sql-driver/mysql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 func NewDatabaseConnection() (result *sql.DB, err error) {
@@ -24,11 +27,41 @@ func NewDatabaseConnection() (result *sql.DB, err error) {
 		Addr:                 fmt.Sprintf("%s:%s", HOST, PORT),
 		DBName:               DBNAME,
 		AllowNativePasswords: true,
+		ParseTime:            true,
+		// UPDATE reports matched rather than changed rows, so controllers can tell a missing row from an unchanged one.
+		ClientFoundRows: true,
 	}
-	result, err = sql.Open(DRIVER, cfg.FormatDSN())
+	result, err = otelsql.Open(DRIVER, cfg.FormatDSN(),
+		otelsql.WithAttributes(semconv.DBSystemMySQL, semconv.DBNamespace(DBNAME)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	return
 }
+
+const duplicateEntry = 1062
+
+// duplicateChecks recognise unique key violations, one per driver in use.
+var duplicateChecks = []func(err error) bool{
+	func(err error) bool {
+		var mysqlErr *mysql.MySQLError
+		return errors.As(err, &mysqlErr) && mysqlErr.Number == duplicateEntry
+	},
+}
+
+// RegisterDuplicateCheck teaches IsDuplicate another driver's unique key violation, as the test database does for sqlite.
+func RegisterDuplicateCheck(check func(err error) bool) {
+	duplicateChecks = append(duplicateChecks, check)
+}
+
+func IsDuplicate(err error) bool {
+	for _, check := range duplicateChecks {
+		if check(err) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// Package dbtest gives each test its own sqlite database built from the same migrations as MySQL,
+// so controller and view tests run offline and in parallel.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/scripts"
+)
+
+func init() {
+	database.RegisterDuplicateCheck(func(err error) bool {
+		var sqliteErr sqlite3.Error
+		return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	})
+}
+
+// New opens an empty database with every migration applied; it is closed and removed when t ends.
+func New(t testing.TB) (result *sql.DB) {
+	t.Helper()
+	result = NewAt(t, "")
+	Upgrade(t, result, "")
+	return
+}
+
+// NewAt opens an empty database migrated up to and including version, so tests can seed data in an older schema
+// before Upgrade runs the migrations after it.
+func NewAt(t testing.TB, version string) (result *sql.DB) {
+	t.Helper()
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") + "?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000"
+	result, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { result.Close() })
+	if version != "" {
+		if err = apply(result, func(v string) bool { return v <= version }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return
+}
+
+// Upgrade applies the migrations after version, every one of them when version is empty.
+func Upgrade(t testing.TB, db *sql.DB, version string) {
+	t.Helper()
+	if err := TryUpgrade(db, version); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TryUpgrade is Upgrade for tests expecting a migration to stop; the migrations before the failing statement stay applied.
+func TryUpgrade(db *sql.DB, version string) error {
+	return apply(db, func(v string) bool { return v > version })
+}
+
+func apply(db *sql.DB, wanted func(version string) bool) error {
+	statements, err := schema(wanted)
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		if err = database.ExecMigration(context.Background(), db, statement); err != nil {
+			return fmt.Errorf("%s: %w", statement, err)
+		}
+	}
+	return nil
+}
+
+func schema(wanted func(version string) bool) (result []string, err error) {
+	files, err := fs.Glob(scripts.Migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if !wanted(strings.TrimSuffix(path.Base(file), ".sql")) {
+			continue
+		}
+		content, err := fs.ReadFile(scripts.Migrations, file)
+		if err != nil {
+			return nil, err
+		}
+		for _, statement := range strings.Split(string(content), ";\n") {
+			if statement = translate(strings.TrimSpace(statement)); statement != "" {
+				result = append(result, statement)
+			}
+		}
+	}
+	return
+}
+
+var (
+	createTable = regexp.MustCompile(`(?s)^(CREATE TABLE [^(]+\()\n(.*)\n\)$`)
+	uniqueKey   = regexp.MustCompile(`^UNIQUE KEY \w+ `)
+	indexKey    = regexp.MustCompile(`^KEY \w+ `)
+	precision   = regexp.MustCompile(`DATETIME\(\d\)`)
+	addForeign  = regexp.MustCompile(`^(ALTER TABLE \w+ ADD COLUMN .*), ADD FOREIGN KEY \(\w+\) (REFERENCES .*)$`)
+	modify      = regexp.MustCompile(`^ALTER TABLE \w+ MODIFY `)
+)
+
+// translate rewrites the few MySQL constructs the migrations use: named and plain keys, column constraints
+// interleaved with columns, which sqlite only accepts at the end, fractional DATETIME and INSERT IGNORE. A column
+// added with its foreign key becomes an inline REFERENCES, and MODIFY, which sqlite cannot express, is dropped, so
+// the NOT NULL it tightens is only enforced by MySQL.
+func translate(statement string) string {
+	if modify.MatchString(statement) {
+		return ""
+	}
+	statement = addForeign.ReplaceAllString(statement, "$1 $2")
+	statement = strings.ReplaceAll(statement, "INSERT IGNORE", "INSERT OR IGNORE")
+	statement = precision.ReplaceAllString(statement, "DATETIME")
+	match := createTable.FindStringSubmatch(statement)
+	if match == nil {
+		return statement
+	}
+	var columns, constraints []string
+	for _, line := range strings.Split(match[2], "\n") {
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		switch {
+		case indexKey.MatchString(line):
+		case uniqueKey.MatchString(line):
+			constraints = append(constraints, uniqueKey.ReplaceAllString(line, "UNIQUE "))
+		case strings.HasPrefix(line, "PRIMARY KEY"), strings.HasPrefix(line, "FOREIGN KEY"):
+			constraints = append(constraints, line)
+		default:
+			columns = append(columns, line)
+		}
+	}
+	return fmt.Sprintf("%s\n  %s\n)", match[1], strings.Join(append(columns, constraints...), ",\n  "))
+}
@@ -0,0 +1,68 @@
+package dbtest_test
+
+import (
+	"testing"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAppliesMigrationsInIsolation(t *testing.T) {
+	t.Parallel()
+	db := dbtest.New(t)
+	var version string
+	err := db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version)
+	assert.Nil(t, err)
+	assert.Equal(t, "0009_movies_name_director_year_unique", version)
+
+	_, err = db.Exec("INSERT INTO cinemas(id, name) VALUES('cinema-id', 'cinema')")
+	assert.Nil(t, err)
+	_, err = db.Exec("INSERT INTO rooms(id, fk_cinema_id, number, description) VALUES('room-id', 'missing', 1, 'room')")
+	assert.NotNil(t, err)
+
+	insert := "INSERT INTO movies(id, name, director, year, duration_in_seconds) VALUES(?, 'name', 'director', 1999, 3600)"
+	db.Exec(insert, "first")
+	_, err = db.Exec(insert, "second")
+	assert.True(t, database.IsDuplicate(err))
+
+	var cinemas int
+	dbtest.New(t).QueryRow("SELECT COUNT(1) FROM cinemas").Scan(&cinemas)
+	assert.Equal(t, 0, cinemas)
+}
+
+func TestUpgradeFromBaselineSchema(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewAt(t, "0001_initial_schema")
+	_, err := db.Exec("INSERT INTO movies(id, name, director, duration_in_seconds) VALUES('movie-id', 'name', 'director', 3600), ('copy-id', 'name', 'director', 3600)")
+	assert.Nil(t, err)
+	_, err = db.Exec("INSERT INTO rooms(id, number, description) VALUES('room-id', 1, 'room'), ('copy-id', 1, 'room')")
+	assert.Nil(t, err)
+	applied := func() (result string) {
+		db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&result)
+		return
+	}
+
+	err = dbtest.TryUpgrade(db, applied())
+	assert.ErrorContains(t, err, "rooms share a number within a cinema")
+	assert.Equal(t, "0007_audit_logs_cinema", applied())
+	db.Exec("UPDATE rooms SET number = 2 WHERE id = 'copy-id'")
+	err = dbtest.TryUpgrade(db, applied())
+	assert.ErrorContains(t, err, "movies share a name, director and year")
+	assert.Equal(t, "0008_rooms_cinema_number_unique", applied())
+	db.Exec("UPDATE movies SET year = 1999 WHERE id = 'copy-id'")
+	assert.Nil(t, dbtest.TryUpgrade(db, applied()))
+	assert.Equal(t, "0009_movies_name_director_year_unique", applied())
+
+	var cinemaId, cinemaName string
+	assert.Nil(t, db.QueryRow("SELECT fk_cinema_id FROM rooms WHERE id = 'room-id'").Scan(&cinemaId))
+	assert.Nil(t, db.QueryRow("SELECT name FROM cinemas WHERE id = ?", cinemaId).Scan(&cinemaName))
+	assert.Equal(t, "Default cinema", cinemaName)
+	var year int
+	assert.Nil(t, db.QueryRow("SELECT year FROM movies WHERE id = 'movie-id'").Scan(&year))
+	assert.Equal(t, 0, year)
+	_, err = db.Exec("INSERT INTO rooms(id, fk_cinema_id, number, description) VALUES('other-room-id', ?, 1, 'room')", cinemaId)
+	assert.True(t, database.IsDuplicate(err))
+	_, err = db.Exec("INSERT INTO movies(id, name, director, year, duration_in_seconds) VALUES('other-movie-id', 'name', 'director', 0, 3600)")
+	assert.True(t, database.IsDuplicate(err))
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Migrate applies every *.sql file of fsys whose version (the file name without extension) is not in schema_migrations yet.
+// MySQL commits DDL implicitly, so a failing file stops the run and must be fixed forward; files that can fail on existing
+// data check it first and hold a single DDL statement, so they run again once the data is fixed.
+func Migrate(ctx context.Context, db *sql.DB, fsys fs.FS) (result []string, err error) {
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(100),
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY(version)
+		)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		version := strings.TrimSuffix(path.Base(file), ".sql")
+		if applied[version] {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return result, err
+		}
+		for _, statement := range statements(string(content)) {
+			if err = ExecMigration(ctx, db, statement); err != nil {
+				return result, fmt.Errorf("%s: %w", version, err)
+			}
+		}
+		_, err = db.ExecContext(ctx, `INSERT IGNORE INTO schema_migrations(version) VALUES (?)`, version)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, version)
+	}
+	return
+}
+
+// ExecMigration runs one migration statement. A SELECT is a check: a row it returns stops the migration, its text
+// becoming the error, before the statements after it change anything.
+func ExecMigration(ctx context.Context, db *sql.DB, statement string) error {
+	if !isCheck(statement) {
+		_, err := db.ExecContext(ctx, statement)
+		return err
+	}
+	var message string
+	err := db.QueryRowContext(ctx, statement).Scan(&message)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return errors.New(message)
+}
+
+func isCheck(statement string) bool {
+	for _, line := range strings.Split(statement, "\n") {
+		if line = strings.TrimSpace(line); !strings.HasPrefix(line, "--") {
+			return strings.HasPrefix(strings.ToUpper(line), "SELECT ")
+		}
+	}
+	return false
+}
+
+func appliedVersions(ctx context.Context, db *sql.DB) (result map[string]bool, err error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result = map[string]bool{}
+	for rows.Next() {
+		var version string
+		rows.Scan(&version)
+		result[version] = true
+	}
+	return result, rows.Err()
+}
+
+// statements splits a file on semicolons ending a line; migrations keep semicolons out of string literals.
+func statements(content string) (result []string) {
+	for _, statement := range strings.Split(content, ";\n") {
+		statement = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(statement), ";"))
+		if statement != "" {
+			result = append(result, statement)
+		}
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+type txKey struct{}
+
+// Tx is a transaction that only commits or rolls back when it owns the underlying *sql.Tx;
+// when joined to a transaction carried by the context, the owner of that transaction decides.
+type Tx struct {
+	*sql.Tx
+	joined bool
+}
+
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+func TxFrom(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sql.Tx)
+	return tx
+}
+
+// BeginTx joins the transaction carried by ctx, or starts a new one on db.
+func BeginTx(ctx context.Context, db *sql.DB) (result *Tx, err error) {
+	if tx := TxFrom(ctx); tx != nil {
+		return &Tx{Tx: tx, joined: true}, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+func (t *Tx) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *Tx) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
+
+// InTx runs fn with a transaction carried by its context, committing when fn succeeds.
+func InTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(WithTx(ctx, tx))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// QuerierFrom reads through the transaction carried by ctx, so rows it wrote but has not committed are visible.
+func QuerierFrom(ctx context.Context, db *sql.DB) Querier {
+	if tx := TxFrom(ctx); tx != nil {
+		return tx
+	}
+	return db
+}
+
+// Affected answers notFound when a statement matched no row, e.g. one deleted since it was read.
+func Affected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
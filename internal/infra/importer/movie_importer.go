@@ -0,0 +1,261 @@
+package importer
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	"github.com/rochaeduardo997/irede_golang_dev/pkg/tabular"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+)
+
+var errDryRun = errors.New("dry run")
+
+type Options struct {
+	Format string
+	// DryRun applies every row inside a transaction that is rolled back, so the report matches a real run.
+	DryRun bool
+	// Upsert updates the movie sharing the name, director and year instead of reporting a conflict.
+	Upsert bool
+}
+
+type Row struct {
+	Line   int    `json:"line"`
+	Id     string `json:"id,omitempty"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	DryRun  bool   `json:"dryRun"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+	Failed  int    `json:"failed"`
+	Rows    []*Row `json:"rows"`
+}
+
+type ParsedMovie struct {
+	Line  int
+	Movie *model_movie.Movie
+	Err   error
+}
+
+type MovieImporter struct {
+	Db         *sql.DB
+	Controller controller_interfaces.IGenericController[model_movie.Movie]
+}
+
+func ParseFormat(value string) (result string, err error) {
+	switch strings.ToLower(value) {
+	case "csv", "text/csv":
+		return FormatCSV, nil
+	case "ndjson", "jsonl", "application/x-ndjson", "application/jsonl", "application/json-lines":
+		return FormatNDJSON, nil
+	}
+	return "", errors.New("format must be csv or ndjson")
+}
+
+func (mi *MovieImporter) Import(ctx context.Context, r io.Reader, opts *Options) (result *Report, err error) {
+	movies, err := ParseMovies(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	result = &Report{DryRun: opts.DryRun}
+	if !opts.DryRun {
+		mi.apply(ctx, movies, opts, result)
+		return result.count(), nil
+	}
+	err = database.InTx(ctx, mi.Db, func(ctx context.Context) error {
+		mi.apply(ctx, movies, opts, result)
+		return errDryRun
+	})
+	if !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return result.count(), nil
+}
+
+func (mi *MovieImporter) apply(ctx context.Context, movies []*ParsedMovie, opts *Options, report *Report) {
+	for _, parsed := range movies {
+		row := &Row{Line: parsed.Line}
+		report.Rows = append(report.Rows, row)
+		if parsed.Err != nil {
+			row.Error = parsed.Err.Error()
+			continue
+		}
+		id, err := mi.Controller.Create(ctx, parsed.Movie)
+		row.Id, row.Action = id, ActionCreated
+		var conflict *controller_interfaces.ConflictError
+		if errors.As(err, &conflict) && opts.Upsert {
+			_, err = mi.Controller.UpdateBy(ctx, conflict.ExistingId, parsed.Movie)
+			row.Id, row.Action = conflict.ExistingId, ActionUpdated
+		}
+		if err != nil {
+			row.Id, row.Action, row.Error = "", "", err.Error()
+		}
+	}
+}
+
+func (r *Report) count() *Report {
+	for _, row := range r.Rows {
+		switch {
+		case row.Error != "":
+			r.Failed++
+		case row.Action == ActionUpdated:
+			r.Updated++
+		default:
+			r.Created++
+		}
+	}
+	return r
+}
+
+// ParseMovies reads every row, validating it through Movie.IsValid; row problems are kept on the row, only unreadable input fails.
+func ParseMovies(r io.Reader, format string) (result []*ParsedMovie, err error) {
+	switch format {
+	case FormatCSV:
+		result, err = parseCSV(r)
+	case FormatNDJSON:
+		result, err = parseNDJSON(r)
+	default:
+		return nil, errors.New("format must be csv or ndjson")
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]int{}
+	for _, parsed := range result {
+		if parsed.Err != nil {
+			continue
+		}
+		if parsed.Err = parsed.Movie.IsValid(); parsed.Err != nil {
+			continue
+		}
+		key := fmt.Sprintf("%s\x00%s\x00%d", parsed.Movie.Name, parsed.Movie.Director, parsed.Movie.Year)
+		if line, found := seen[key]; found {
+			parsed.Err = fmt.Errorf("duplicates line %d", line)
+			continue
+		}
+		seen[key] = parsed.Line
+	}
+	return
+}
+
+func parseCSV(r io.Reader) (result []*ParsedMovie, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.NewReplacer("_", "", " ", "").Replace(name))
+		if name == "durationinseconds" {
+			name = "duration"
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"name", "director", "duration"} {
+		if _, found := columns[required]; !found {
+			return nil, errors.New("csv header must include name, director and duration")
+		}
+	}
+	for {
+		record, readErr := reader.Read()
+		if readErr == io.EOF {
+			return
+		}
+		var parseErr *csv.ParseError
+		if errors.As(readErr, &parseErr) {
+			result = append(result, &ParsedMovie{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+		line, _ := reader.FieldPos(0)
+		parsed := &ParsedMovie{Line: line, Movie: &model_movie.Movie{}}
+		field := func(name string) string {
+			i, found := columns[name]
+			if !found {
+				return ""
+			}
+			return tabular.Unquote(strings.TrimSpace(record[i]))
+		}
+		parsed.Movie.Name = field("name")
+		parsed.Movie.Director = field("director")
+		parsed.Movie.DurationInSeconds, parsed.Err = parseDuration(field("duration"))
+		if year := field("year"); year != "" && parsed.Err == nil {
+			parsed.Movie.Year, parsed.Err = parseYear(year)
+		}
+		result = append(result, parsed)
+	}
+}
+
+type ndjsonMovie struct {
+	Name              string
+	Director          string
+	Year              uint16
+	DurationInSeconds json.RawMessage
+	Duration          json.RawMessage
+}
+
+func parseNDJSON(r io.Reader) (result []*ParsedMovie, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		parsed := &ParsedMovie{Line: line}
+		result = append(result, parsed)
+		input := &ndjsonMovie{}
+		if parsed.Err = json.Unmarshal([]byte(text), input); parsed.Err != nil {
+			continue
+		}
+		duration := input.DurationInSeconds
+		if len(duration) == 0 {
+			duration = input.Duration
+		}
+		parsed.Movie = &model_movie.Movie{Name: input.Name, Director: input.Director, Year: input.Year}
+		parsed.Movie.DurationInSeconds, parsed.Err = parseDuration(strings.Trim(string(duration), `"`))
+	}
+	return result, scanner.Err()
+}
+
+// parseDuration leaves a missing duration at zero so Movie.IsValid reports it.
+func parseDuration(value string) (uint16, error) {
+	if value == "" || value == "null" {
+		return 0, nil
+	}
+	return model_movie.ParseDuration(value)
+}
+
+func parseYear(value string) (result uint16, err error) {
+	year, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return 0, errors.New("movie year must be a number")
+	}
+	return uint16(year), nil
+}
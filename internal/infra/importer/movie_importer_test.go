@@ -0,0 +1,101 @@
+package importer_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/importer"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	"github.com/rochaeduardo997/irede_golang_dev/pkg/tabular"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeController struct {
+	controller_interfaces.IGenericController[model_movie.Movie]
+	movies map[string]*model_movie.Movie
+}
+
+func (fc *fakeController) Create(ctx context.Context, m *model_movie.Movie) (string, error) {
+	for id, movie := range fc.movies {
+		if movie.Name == m.Name && movie.Director == m.Director && movie.Year == m.Year {
+			return "", &controller_interfaces.ConflictError{Entity: "movie", ExistingId: id}
+		}
+	}
+	m.Id = m.Name
+	fc.movies[m.Id] = m
+	return m.Id, nil
+}
+
+func (fc *fakeController) UpdateBy(ctx context.Context, id string, m *model_movie.Movie) (bool, error) {
+	fc.movies[id] = m
+	return true, nil
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "Name,Director,Year,Duration_In_Seconds\n" +
+		"matrix,wachowski,1999,01:36:00\n" +
+		"alien,scott,,7020\n" +
+		",scott,1979,7020\n" +
+		"matrix,wachowski,1999,8160\n" +
+		"heat,mann,1995,long\n"
+	result, err := importer.ParseMovies(strings.NewReader(input), importer.FormatCSV)
+	assert.Nil(t, err)
+	assert.Len(t, result, 5)
+	assert.Equal(t, &model_movie.Movie{Name: "matrix", Director: "wachowski", Year: 1999, DurationInSeconds: 5760}, result[0].Movie)
+	assert.Equal(t, 2, result[0].Line)
+	assert.Nil(t, result[1].Err)
+	assert.EqualError(t, result[2].Err, "movie name must be provided")
+	assert.EqualError(t, result[3].Err, "duplicates line 2")
+	assert.EqualError(t, result[4].Err, "movie duration must be seconds or HH:MM:SS")
+}
+
+func TestParseCSVExport(t *testing.T) {
+	buf := &strings.Builder{}
+	w, _ := tabular.NewWriter(buf, tabular.FormatCSV, []string{"id", "name", "director", "year", "durationInSeconds"})
+	w.Row("1", "-1", "@director", uint16(2020), uint16(5760))
+	w.Close()
+	result, err := importer.ParseMovies(strings.NewReader(buf.String()), importer.FormatCSV)
+	assert.Nil(t, err)
+	assert.Equal(t, &model_movie.Movie{Name: "-1", Director: "@director", Year: 2020, DurationInSeconds: 5760}, result[0].Movie)
+}
+
+func TestFailParseCSVWithoutRequiredColumns(t *testing.T) {
+	_, err := importer.ParseMovies(strings.NewReader("name,year\nmatrix,1999\n"), importer.FormatCSV)
+	assert.EqualError(t, err, "csv header must include name, director and duration")
+}
+
+func TestParseNDJSON(t *testing.T) {
+	input := `{"name":"matrix","director":"wachowski","year":1999,"durationInSeconds":5760}` + "\n\n" +
+		`{"name":"alien","director":"scott","duration":"01:57:00"}` + "\n" +
+		`{"name":` + "\n"
+	result, err := importer.ParseMovies(strings.NewReader(input), importer.FormatNDJSON)
+	assert.Nil(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, uint16(5760), result[0].Movie.DurationInSeconds)
+	assert.Equal(t, 3, result[1].Line)
+	assert.Equal(t, uint16(7020), result[1].Movie.DurationInSeconds)
+	assert.NotNil(t, result[2].Err)
+}
+
+func TestImportWithUpsert(t *testing.T) {
+	controller := &fakeController{movies: map[string]*model_movie.Movie{
+		"existing": {Id: "existing", Name: "matrix", Director: "wachowski", Year: 1999, DurationInSeconds: 60},
+	}}
+	mi := &importer.MovieImporter{Controller: controller}
+	input := "name,director,year,duration\nmatrix,wachowski,1999,5760\nalien,scott,1979,7020\nheat,,1995,100\n"
+
+	report, err := mi.Import(context.Background(), strings.NewReader(input), &importer.Options{Format: importer.FormatCSV})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, "movie already exists with id existing", report.Rows[0].Error)
+
+	report, err = mi.Import(context.Background(), strings.NewReader(input), &importer.Options{Format: importer.FormatCSV, Upsert: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, report.Updated)
+	assert.Equal(t, &importer.Row{Line: 2, Id: "existing", Action: importer.ActionUpdated}, report.Rows[0])
+	assert.Equal(t, uint16(5760), controller.movies["existing"].DurationInSeconds)
+	assert.Equal(t, "movie director must be provided", report.Rows[2].Error)
+}
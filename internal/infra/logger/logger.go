@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func NewLogger(w io.Writer) (result *slog.Logger) {
+	opts := &slog.HandlerOptions{Level: level(os.Getenv("LOG_LEVEL"))}
+	var handler slog.Handler
+	switch strings.ToLower(os.Getenv("LOG_FORMAT")) {
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	result = slog.New(&ContextHandler{Handler: handler})
+	return
+}
+
+func level(value string) (result slog.Level) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+type ContextHandler struct{ slog.Handler }
+
+func (ch *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		return ch.Handler.Handle(ctx, r)
+	}
+	if id := http_adapter.RequestIDFrom(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return ch.Handler.Handle(ctx, r)
+}
+
+func (ch *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: ch.Handler.WithAttrs(attrs)}
+}
+
+func (ch *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: ch.Handler.WithGroup(name)}
+}
@@ -0,0 +1,54 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/logger"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerWritesJSON(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := logger.NewLogger(buffer)
+	l.InfoContext(context.Background(), "message", "key", "value")
+
+	line := map[string]any{}
+	err := json.Unmarshal(buffer.Bytes(), &line)
+	assert.Nil(t, err)
+	assert.Equal(t, "message", line["msg"])
+	assert.Equal(t, "value", line["key"])
+	assert.Nil(t, line["request_id"])
+}
+
+func TestLoggerTagsRequestID(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := logger.NewLogger(buffer)
+	handler := http_adapter.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.With("entity", "movie").InfoContext(r.Context(), "message")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(http_adapter.RequestIDHeader, "request-id")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := map[string]any{}
+	err := json.Unmarshal(buffer.Bytes(), &line)
+	assert.Nil(t, err)
+	assert.Equal(t, "request-id", line["request_id"])
+	assert.Equal(t, "movie", line["entity"])
+}
+
+func TestLoggerLevelFromEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	buffer := &bytes.Buffer{}
+	l := logger.NewLogger(buffer)
+	l.Info("ignored")
+	assert.Equal(t, 0, buffer.Len())
+	l.Warn("written")
+	assert.Contains(t, buffer.String(), "written")
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+)
+
+type InstrumentedController[T any] struct {
+	Name       string
+	Metrics    *Metrics
+	Controller controller_interfaces.IGenericController[T]
+}
+
+func NewInstrumentedController[T any](ic *InstrumentedController[T]) (result controller_interfaces.IGenericController[T]) {
+	result = ic
+	return
+}
+
+func (ic *InstrumentedController[T]) Create(ctx context.Context, m *T) (result string, err error) {
+	defer ic.observe("Create", time.Now(), &err)
+	return ic.Controller.Create(ctx, m)
+}
+
+func (ic *InstrumentedController[T]) FindBy(ctx context.Context, id string) (result *T, err error) {
+	defer ic.observe("FindBy", time.Now(), &err)
+	return ic.Controller.FindBy(ctx, id)
+}
+
+func (ic *InstrumentedController[T]) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[T], err error) {
+	defer ic.observe("FindAll", time.Now(), &err)
+	return ic.Controller.FindAll(ctx, page)
+}
+
+func (ic *InstrumentedController[T]) UpdateBy(ctx context.Context, id string, m *T) (result bool, err error) {
+	defer ic.observe("UpdateBy", time.Now(), &err)
+	return ic.Controller.UpdateBy(ctx, id, m)
+}
+
+func (ic *InstrumentedController[T]) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	defer ic.observe("DeleteBy", time.Now(), &err)
+	return ic.Controller.DeleteBy(ctx, id)
+}
+
+func (ic *InstrumentedController[T]) observe(method string, start time.Time, err *error) {
+	ic.Metrics.ObserveQuery(ic.Name, method, start, *err)
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+type Metrics struct {
+	Registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+}
+
+func NewMetrics(db *sql.DB) (result *Metrics) {
+	result = &Metrics{Registry: prometheus.NewRegistry()}
+	result.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+	result.requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route template.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	result.queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database time spent per controller method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"controller", "method", "result"})
+	result.Registry.MustRegister(
+		result.requests,
+		result.requestDuration,
+		result.queryDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	if db != nil {
+		result.Registry.MustRegister(collectors.NewDBStatsCollector(db, "mysql"))
+	}
+	return
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+func (m *Metrics) Middleware() http_adapter.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			r = http_adapter.WithRouteCapture(r)
+			sr := &http_adapter.StatusRecorder{ResponseWriter: w}
+			next.ServeHTTP(sr, r)
+			if sr.Status == 0 {
+				sr.Status = http.StatusOK
+			}
+			route := http_adapter.RoutePattern(r.Context())
+			if route == "" {
+				route = "unmatched"
+			}
+			m.requests.WithLabelValues(r.Method, route, strconv.Itoa(sr.Status)).Inc()
+			m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+func (m *Metrics) ObserveQuery(controller, method string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.queryDuration.WithLabelValues(controller, method, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,66 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/metrics"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeControllerMovie struct {
+	controller_interfaces.IGenericController[model_movie.Movie]
+}
+
+func (fcm *fakeControllerMovie) FindBy(ctx context.Context, id string) (result *model_movie.Movie, err error) {
+	return nil, errors.New("movie not found")
+}
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	assert.Nil(t, err)
+	return string(body)
+}
+
+func TestMiddlewareLabelsByRouteTemplate(t *testing.T) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) {
+			m := metrics.NewMetrics(nil)
+			httpAdapter, handler := newAdapter()
+			httpAdapter.Use(m.Middleware())
+			httpAdapter.AddRoute("get", "/api/v1/movies/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/movies/1", nil))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/movies/2", nil))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+			body := scrape(t, m)
+			assert.Contains(t, body, `http_requests_total{method="GET",route="/api/v1/movies/{id}",status="200"} 2`)
+			assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+			assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/api/v1/movies/{id}"} 2`)
+			assert.NotContains(t, body, `/api/v1/movies/1"`)
+		})
+	}
+}
+
+func TestInstrumentedController(t *testing.T) {
+	m := metrics.NewMetrics(nil)
+	cm := metrics.NewInstrumentedController(&metrics.InstrumentedController[model_movie.Movie]{Name: "movie", Metrics: m, Controller: &fakeControllerMovie{}})
+
+	_, err := cm.FindBy(context.Background(), "id")
+	assert.EqualError(t, err, "movie not found")
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `db_query_duration_seconds_count{controller="movie",method="FindBy",result="error"} 1`)
+}
@@ -0,0 +1,129 @@
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+)
+
+// DefaultSeed is used by tests and the CLI unless told otherwise, so everyone looks at the same data.
+const DefaultSeed = 1
+
+type Volume struct {
+	Cinemas        int
+	Movies         int
+	RoomsPerCinema int
+	MoviesPerRoom  int
+}
+
+var DefaultVolume = Volume{Cinemas: 2, Movies: 40, RoomsPerCinema: 5, MoviesPerRoom: 4}
+
+var (
+	adjectives = []string{"Silent", "Last", "Hidden", "Golden", "Broken", "Distant", "Wild", "Midnight", "Frozen", "Burning", "Lost", "Crimson", "Endless", "Secret", "Northern", "Quiet"}
+	nouns      = []string{"River", "Horizon", "Station", "Garden", "Empire", "Letter", "Harbor", "Desert", "Signal", "Mirror", "Voyage", "Orchard", "Frontier", "Lighthouse", "Kingdom", "Promise"}
+	firstNames = []string{"Ana", "Bruno", "Carla", "Diego", "Elisa", "Fabio", "Helena", "Igor", "Julia", "Kleber", "Luana", "Marcos", "Nina", "Otavio", "Paula", "Rafael"}
+	lastNames  = []string{"Almeida", "Barbosa", "Cardoso", "Duarte", "Esteves", "Ferraz", "Gomes", "Holanda", "Lima", "Moura", "Nogueira", "Pacheco", "Queiroz", "Ribeiro", "Sampaio", "Teixeira"}
+	cinemas    = []string{"Aurora", "Iracema", "Paradiso", "Odeon", "Marrocos", "Rex", "Metro", "Art", "Dragao", "Estrela"}
+	cities     = []string{"Fortaleza", "Recife", "Salvador", "Natal", "Sao Luis", "Teresina", "Joao Pessoa", "Maceio"}
+	formats    = []string{"2D", "3D", "IMAX", "VIP"}
+)
+
+// Generator builds valid cinemas, movies and rooms from a seed; the same seed always yields the same sequence.
+// Movie and cinema names are unique per generator, matching how seed files refer to them.
+type Generator struct {
+	rand    *rand.PCG
+	names   map[string]bool
+	numbers map[string]uint16
+}
+
+func NewGenerator(seed uint64) (result *Generator) {
+	return &Generator{rand: rand.NewPCG(seed, seed), names: map[string]bool{}, numbers: map[string]uint16{}}
+}
+
+// intN draws from the PCG source directly so values stay stable across Go releases.
+func (g *Generator) intN(n int) int {
+	return int(g.rand.Uint64() % uint64(n))
+}
+
+func (g *Generator) pick(values []string) string {
+	return values[g.intN(len(values))]
+}
+
+func (g *Generator) unique(name string) string {
+	result := name
+	for i := 2; g.names[result]; i++ {
+		result = fmt.Sprintf("%s %d", name, i)
+	}
+	g.names[result] = true
+	return result
+}
+
+func (g *Generator) Cinema() *model_cinema.Cinema {
+	return &model_cinema.Cinema{Name: g.unique("Cine " + g.pick(cinemas)), City: g.pick(cities)}
+}
+
+func (g *Generator) Movie() *model_movie.Movie {
+	return &model_movie.Movie{
+		Name:              g.unique("The " + g.pick(adjectives) + " " + g.pick(nouns)),
+		Director:          g.pick(firstNames) + " " + g.pick(lastNames),
+		Year:              uint16(1960 + g.intN(65)),
+		DurationInSeconds: uint16(80+g.intN(100)) * 60,
+	}
+}
+
+// Room numbers count up per cinema starting at 1.
+func (g *Generator) Room(cinemaId string, movies ...*model_movie.Movie) *model_room.Room {
+	g.numbers[cinemaId]++
+	number := g.numbers[cinemaId]
+	return &model_room.Room{
+		CinemaId:    cinemaId,
+		Number:      number,
+		Description: fmt.Sprintf("Sala %d %s", number, g.pick(formats)),
+		Movies:      movies,
+	}
+}
+
+// File generates a seed file with v's volume; each room shows MoviesPerRoom distinct movies.
+func (g *Generator) File(v Volume) (result *File, err error) {
+	if v.Cinemas < 0 || v.Movies < 0 || v.RoomsPerCinema < 0 || v.MoviesPerRoom < 0 {
+		return nil, errors.New("volume must not be negative")
+	}
+	if v.MoviesPerRoom > v.Movies {
+		return nil, errors.New("movies per room must be at most the number of movies")
+	}
+	result = &File{}
+	for range v.Movies {
+		m := g.Movie()
+		result.Movies = append(result.Movies, &Movie{Name: m.Name, Director: m.Director, Year: m.Year, DurationInSeconds: m.DurationInSeconds})
+	}
+	for range v.Cinemas {
+		c := g.Cinema()
+		result.Cinemas = append(result.Cinemas, &Cinema{Name: c.Name, City: c.City})
+		for range v.RoomsPerCinema {
+			r := g.Room(c.Name)
+			room := &Room{Cinema: c.Name, Number: r.Number, Description: r.Description, Movies: []string{}}
+			for _, i := range g.sample(v.Movies, v.MoviesPerRoom) {
+				room.Movies = append(room.Movies, result.Movies[i].Name)
+			}
+			result.Rooms = append(result.Rooms, room)
+		}
+	}
+	return
+}
+
+// sample returns k distinct indexes below n, in drawing order.
+func (g *Generator) sample(n, k int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	for i := range k {
+		j := i + g.intN(n-i)
+		indexes[i], indexes[j] = indexes[j], indexes[i]
+	}
+	return indexes[:k]
+}
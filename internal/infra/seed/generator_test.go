@@ -0,0 +1,57 @@
+package seed_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/seed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratorIsDeterministic(t *testing.T) {
+	first, err := seed.NewGenerator(seed.DefaultSeed).File(seed.DefaultVolume)
+	assert.Nil(t, err)
+	second, _ := seed.NewGenerator(seed.DefaultSeed).File(seed.DefaultVolume)
+	assert.Equal(t, first, second)
+
+	other, _ := seed.NewGenerator(seed.DefaultSeed + 1).File(seed.DefaultVolume)
+	assert.NotEqual(t, first, other)
+
+	g := seed.NewGenerator(seed.DefaultSeed)
+	assert.Equal(t, g.Movie(), seed.NewGenerator(seed.DefaultSeed).Movie())
+	assert.NotEqual(t, g.Movie().Name, seed.NewGenerator(seed.DefaultSeed).Movie().Name)
+}
+
+func TestGeneratorVolume(t *testing.T) {
+	volume := seed.Volume{Cinemas: 3, Movies: 300, RoomsPerCinema: 4, MoviesPerRoom: 5}
+	f, err := seed.NewGenerator(seed.DefaultSeed).File(volume)
+	assert.Nil(t, err)
+	assert.Len(t, f.Cinemas, 3)
+	assert.Len(t, f.Movies, 300)
+	assert.Len(t, f.Rooms, 12)
+
+	names := map[string]bool{}
+	for _, movie := range f.Movies {
+		assert.False(t, names[movie.Name], movie.Name)
+		names[movie.Name] = true
+	}
+	for i, room := range f.Rooms {
+		assert.Equal(t, uint16(i%4+1), room.Number)
+		assert.Len(t, room.Movies, 5)
+		seen := map[string]bool{}
+		for _, name := range room.Movies {
+			assert.True(t, names[name])
+			assert.False(t, seen[name])
+			seen[name] = true
+		}
+	}
+
+	result, err := newSeeder().Apply(context.Background(), f)
+	assert.Nil(t, err)
+	assert.Equal(t, &seed.Result{Cinemas: 3, Movies: 300, Rooms: 12}, result)
+}
+
+func TestGeneratorRejectsImpossibleVolume(t *testing.T) {
+	_, err := seed.NewGenerator(seed.DefaultSeed).File(seed.Volume{Movies: 2, MoviesPerRoom: 3})
+	assert.EqualError(t, err, "movies per room must be at most the number of movies")
+}
@@ -0,0 +1,151 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+)
+
+// File describes the data to seed; rooms point at their cinema and movies by name so the file needs no ids.
+type File struct {
+	Cinemas []*Cinema `json:"cinemas"`
+	Movies  []*Movie  `json:"movies"`
+	Rooms   []*Room   `json:"rooms"`
+}
+
+type Cinema struct {
+	Name string `json:"name"`
+	City string `json:"city,omitempty"`
+}
+
+type Movie struct {
+	Name              string `json:"name"`
+	Director          string `json:"director"`
+	Year              uint16 `json:"year,omitempty"`
+	DurationInSeconds uint16 `json:"durationInSeconds"`
+}
+
+type Room struct {
+	Cinema      string   `json:"cinema"`
+	Number      uint16   `json:"number"`
+	Description string   `json:"description"`
+	Movies      []string `json:"movies"`
+}
+
+type Result struct {
+	Cinemas int `json:"cinemas"`
+	Movies  int `json:"movies"`
+	Rooms   int `json:"rooms"`
+}
+
+type Seeder struct {
+	Cinemas controller_interfaces.IGenericController[model_cinema.Cinema]
+	Movies  controller_interfaces.IGenericController[model_movie.Movie]
+	Rooms   controller_interfaces.IGenericController[model_room.Room]
+}
+
+func Load(r io.Reader) (result *File, err error) {
+	result = &File{}
+	err = json.NewDecoder(r).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Apply creates what the file describes and reuses cinemas, movies and rooms that already exist, so seeding twice is harmless.
+func (s *Seeder) Apply(ctx context.Context, f *File) (result *Result, err error) {
+	result = &Result{}
+	cinemas, err := s.existingCinemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, input := range f.Cinemas {
+		if _, found := cinemas[input.Name]; found {
+			continue
+		}
+		cinema := &model_cinema.Cinema{Name: input.Name, City: input.City}
+		if err = cinema.IsValid(); err == nil {
+			_, err = s.Cinemas.Create(ctx, cinema)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cinema %s: %w", cinema.Name, err)
+		}
+		cinemas[cinema.Name] = cinema.Id
+		result.Cinemas++
+	}
+
+	movies := map[string]*model_movie.Movie{}
+	for _, input := range f.Movies {
+		movie := &model_movie.Movie{Name: input.Name, Director: input.Director, Year: input.Year, DurationInSeconds: input.DurationInSeconds}
+		if err = movie.IsValid(); err != nil {
+			return nil, fmt.Errorf("movie %s: %w", movie.Name, err)
+		}
+		_, err = s.Movies.Create(ctx, movie)
+		var conflict *controller_interfaces.ConflictError
+		if errors.As(err, &conflict) {
+			movie.Id, err = conflict.ExistingId, nil
+		} else if err == nil {
+			result.Movies++
+		}
+		if err != nil {
+			return nil, fmt.Errorf("movie %s: %w", movie.Name, err)
+		}
+		if _, found := movies[movie.Name]; !found {
+			movies[movie.Name] = movie
+		}
+	}
+
+	for _, input := range f.Rooms {
+		room := &model_room.Room{CinemaId: cinemas[input.Cinema], Number: input.Number, Description: input.Description}
+		if room.CinemaId == "" {
+			return nil, fmt.Errorf("room %d: unknown cinema %s", input.Number, input.Cinema)
+		}
+		for _, name := range input.Movies {
+			movie, found := movies[name]
+			if !found {
+				return nil, fmt.Errorf("room %d: unknown movie %s", input.Number, name)
+			}
+			room.Movies = append(room.Movies, movie)
+		}
+		if err = room.IsValid(); err != nil {
+			return nil, fmt.Errorf("room %d: %w", input.Number, err)
+		}
+		_, err = s.Rooms.Create(ctx, room)
+		var conflict *controller_interfaces.ConflictError
+		if errors.As(err, &conflict) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("room %d: %w", input.Number, err)
+		}
+		result.Rooms++
+	}
+	return
+}
+
+func (s *Seeder) existingCinemas(ctx context.Context) (result map[string]string, err error) {
+	result = map[string]string{}
+	seen := uint32(0)
+	for page := uint16(1); ; page++ {
+		found, err := s.Cinemas.FindAll(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, cinema := range found.Registers {
+			result[cinema.Name] = cinema.Id
+			seen++
+		}
+		if len(found.Registers) == 0 || seen >= found.Total {
+			return result, nil
+		}
+	}
+}
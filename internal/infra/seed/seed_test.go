@@ -0,0 +1,96 @@
+package seed_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/seed"
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeController[T any] struct {
+	controller_interfaces.IGenericController[T]
+	registers []*T
+	// key identifies a register the way the database unique keys do.
+	key func(*T) string
+	id  func(*T) *string
+}
+
+func (fc *fakeController[T]) Create(ctx context.Context, t *T) (string, error) {
+	for _, register := range fc.registers {
+		if fc.key(register) == fc.key(t) {
+			return "", &controller_interfaces.ConflictError{ExistingId: *fc.id(register)}
+		}
+	}
+	fc.registers = append(fc.registers, t)
+	*fc.id(t) = fmt.Sprint(len(fc.registers))
+	return *fc.id(t), nil
+}
+
+func (fc *fakeController[T]) FindAll(ctx context.Context, page uint16) (*controller_interfaces.FindAllResponse[T], error) {
+	result := &controller_interfaces.FindAllResponse[T]{Total: uint32(len(fc.registers)), Page: page}
+	if page == 1 {
+		result.Registers = fc.registers
+	}
+	return result, nil
+}
+
+func newSeeder() *seed.Seeder {
+	return &seed.Seeder{
+		Cinemas: &fakeController[model_cinema.Cinema]{
+			key: func(c *model_cinema.Cinema) string { return c.Name },
+			id:  func(c *model_cinema.Cinema) *string { return &c.Id },
+		},
+		Movies: &fakeController[model_movie.Movie]{
+			key: func(m *model_movie.Movie) string { return fmt.Sprint(m.Name, m.Director, m.Year) },
+			id:  func(m *model_movie.Movie) *string { return &m.Id },
+		},
+		Rooms: &fakeController[model_room.Room]{
+			key: func(r *model_room.Room) string { return fmt.Sprint(r.CinemaId, r.Number) },
+			id:  func(r *model_room.Room) *string { return &r.Id },
+		},
+	}
+}
+
+func load(t *testing.T) *seed.File {
+	input, err := os.Open("../../../scripts/seed.json")
+	assert.Nil(t, err)
+	defer input.Close()
+	f, err := seed.Load(input)
+	assert.Nil(t, err)
+	return f
+}
+
+func TestApplyIsRepeatable(t *testing.T) {
+	s := newSeeder()
+	result, err := s.Apply(context.Background(), load(t))
+	assert.Nil(t, err)
+	assert.Equal(t, &seed.Result{Cinemas: 1, Movies: 3, Rooms: 2}, result)
+
+	result, err = s.Apply(context.Background(), load(t))
+	assert.Nil(t, err)
+	assert.Equal(t, &seed.Result{}, result)
+
+	rooms := s.Rooms.(*fakeController[model_room.Room]).registers
+	assert.Equal(t, "1", rooms[0].CinemaId)
+	assert.Len(t, rooms[0].Movies, 2)
+	assert.Equal(t, "Alien", rooms[0].Movies[1].Name)
+}
+
+func TestApplyRejectsUnknownNames(t *testing.T) {
+	f := load(t)
+	f.Rooms[0].Movies = append(f.Rooms[0].Movies, "Missing")
+	_, err := newSeeder().Apply(context.Background(), f)
+	assert.EqualError(t, err, "room 1: unknown movie Missing")
+
+	f = load(t)
+	f.Rooms[1].Cinema = "Missing"
+	_, err = newSeeder().Apply(context.Background(), f)
+	assert.EqualError(t, err, "room 2: unknown cinema Missing")
+}
@@ -0,0 +1,48 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrOtherCinema = errors.New("cinema not allowed for these credentials")
+
+type cinemaKey struct{}
+
+// WithCinema scopes everything downstream to one cinema; an empty id leaves ctx unscoped.
+func WithCinema(ctx context.Context, cinemaId string) context.Context {
+	if cinemaId == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, cinemaKey{}, cinemaId)
+}
+
+func CinemaFrom(ctx context.Context) (result string) {
+	result, _ = ctx.Value(cinemaKey{}).(string)
+	return
+}
+
+// Where narrows where, a WHERE clause or empty, to rows whose column holds the caller's cinema; rows of other
+// cinemas then read as missing.
+func Where(ctx context.Context, where, column string, args ...any) (string, []any) {
+	cinemaId := CinemaFrom(ctx)
+	if cinemaId == "" {
+		return where, args
+	}
+	if where == "" {
+		return "WHERE " + column + " = ?", append(args, cinemaId)
+	}
+	return where + " AND " + column + " = ?", append(args, cinemaId)
+}
+
+// Resolve picks the cinema a new record belongs to: scoped callers may only use their own cinema.
+func Resolve(ctx context.Context, requested string) (result string, err error) {
+	cinemaId := CinemaFrom(ctx)
+	if cinemaId == "" {
+		return requested, nil
+	}
+	if requested != "" && requested != cinemaId {
+		return "", ErrOtherCinema
+	}
+	return cinemaId, nil
+}
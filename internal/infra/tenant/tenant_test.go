@@ -0,0 +1,43 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCinema(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", tenant.CinemaFrom(ctx))
+	assert.Equal(t, ctx, tenant.WithCinema(ctx, ""))
+	assert.Equal(t, "cinema-id", tenant.CinemaFrom(tenant.WithCinema(ctx, "cinema-id")))
+}
+
+func TestResolve(t *testing.T) {
+	result, err := tenant.Resolve(context.Background(), "cinema-a")
+	assert.Nil(t, err)
+	assert.Equal(t, "cinema-a", result)
+
+	scoped := tenant.WithCinema(context.Background(), "cinema-a")
+	result, err = tenant.Resolve(scoped, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "cinema-a", result)
+	_, err = tenant.Resolve(scoped, "cinema-b")
+	assert.ErrorIs(t, err, tenant.ErrOtherCinema)
+}
+
+func TestWhere(t *testing.T) {
+	where, args := tenant.Where(context.Background(), "WHERE id = ?", "cinema_id", "id")
+	assert.Equal(t, "WHERE id = ?", where)
+	assert.Equal(t, []any{"id"}, args)
+
+	scoped := tenant.WithCinema(context.Background(), "cinema-a")
+	where, args = tenant.Where(scoped, "WHERE id = ?", "cinema_id", "id")
+	assert.Equal(t, "WHERE id = ? AND cinema_id = ?", where)
+	assert.Equal(t, []any{"id", "cinema-a"}, args)
+	where, args = tenant.Where(scoped, "", "cinema_id")
+	assert.Equal(t, "WHERE cinema_id = ?", where)
+	assert.Equal(t, []any{"cinema-a"}, args)
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TracedController[T any] struct {
+	Name           string
+	TracerProvider trace.TracerProvider
+	Controller     controller_interfaces.IGenericController[T]
+}
+
+func NewTracedController[T any](tc *TracedController[T]) (result controller_interfaces.IGenericController[T]) {
+	result = tc
+	return
+}
+
+func (tc *TracedController[T]) Create(ctx context.Context, m *T) (result string, err error) {
+	ctx, span := tc.start(ctx, "Create")
+	defer tc.end(span, &err)
+	result, err = tc.Controller.Create(ctx, m)
+	span.SetAttributes(attribute.String("entity.id", result))
+	return
+}
+
+func (tc *TracedController[T]) FindBy(ctx context.Context, id string) (result *T, err error) {
+	ctx, span := tc.start(ctx, "FindBy", attribute.String("entity.id", id))
+	defer tc.end(span, &err)
+	return tc.Controller.FindBy(ctx, id)
+}
+
+func (tc *TracedController[T]) FindAll(ctx context.Context, page uint16) (result *controller_interfaces.FindAllResponse[T], err error) {
+	ctx, span := tc.start(ctx, "FindAll", attribute.Int("page", int(page)))
+	defer tc.end(span, &err)
+	result, err = tc.Controller.FindAll(ctx, page)
+	if result != nil {
+		span.SetAttributes(attribute.Int("registers", len(result.Registers)))
+	}
+	return
+}
+
+func (tc *TracedController[T]) UpdateBy(ctx context.Context, id string, m *T) (result bool, err error) {
+	ctx, span := tc.start(ctx, "UpdateBy", attribute.String("entity.id", id))
+	defer tc.end(span, &err)
+	return tc.Controller.UpdateBy(ctx, id, m)
+}
+
+func (tc *TracedController[T]) DeleteBy(ctx context.Context, id string) (result bool, err error) {
+	ctx, span := tc.start(ctx, "DeleteBy", attribute.String("entity.id", id))
+	defer tc.end(span, &err)
+	return tc.Controller.DeleteBy(ctx, id)
+}
+
+func (tc *TracedController[T]) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("controller", tc.Name), attribute.String("controller.method", method))
+	return tc.TracerProvider.Tracer(TracerName).Start(ctx, fmt.Sprintf("%s.%s", tc.Name, method), trace.WithAttributes(attrs...))
+}
+
+func (tc *TracedController[T]) end(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
@@ -0,0 +1,78 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tracing"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type fakeControllerMovie struct {
+	controller_interfaces.IGenericController[model_movie.Movie]
+}
+
+func (fcm *fakeControllerMovie) FindBy(ctx context.Context, id string) (result *model_movie.Movie, err error) {
+	return nil, errors.New("movie not found")
+}
+
+func instanceTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp, exporter
+}
+
+func TestMiddlewareContinuesIncomingTrace(t *testing.T) {
+	tp, exporter := instanceTracerProvider()
+	cm := tracing.NewTracedController(&tracing.TracedController[model_movie.Movie]{Name: "movie", TracerProvider: tp, Controller: &fakeControllerMovie{}})
+	httpAdapter, handler := http_adapter.NewServeMux()
+	httpAdapter.Use(tracing.Middleware(tp))
+	httpAdapter.AddRoute("get", "/api/v1/movies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, err := cm.FindBy(r.Context(), httpAdapter.Param(r, "id"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/movies/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans))
+	controllerSpan, serverSpan := spans[0], spans[1]
+	assert.Equal(t, "GET /api/v1/movies/{id}", serverSpan.Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", serverSpan.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", serverSpan.Parent.SpanID().String())
+	assert.Equal(t, "movie.FindBy", controllerSpan.Name)
+	assert.Equal(t, serverSpan.SpanContext.SpanID(), controllerSpan.Parent.SpanID())
+	assert.Equal(t, codes.Error, controllerSpan.Status.Code)
+	assert.Equal(t, "movie not found", controllerSpan.Status.Description)
+}
+
+func TestMiddlewareMarksServerErrors(t *testing.T) {
+	tp, exporter := instanceTracerProvider()
+	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter.Use(tracing.Middleware(tp))
+	httpAdapter.AddRoute("get", "/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "GET /fail", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.False(t, spans[0].Parent.IsValid())
+}
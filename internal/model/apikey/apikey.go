@@ -0,0 +1,92 @@
+package model_apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const keyPrefix = "irk"
+
+type APIKey struct {
+	Id          string
+	Name        string
+	Prefix      string
+	KeyHash     string
+	Permissions []string
+	RoomIds     []string
+	CinemaId    string
+	CreatedAt   time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+}
+
+func NewAPIKey(k *APIKey) (result *APIKey, err error) {
+	result = k
+	err = result.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (k *APIKey) IsValid() (err error) {
+	if k.Name == "" {
+		return errors.New("api key name must be provided")
+	}
+	if len(k.Permissions) == 0 {
+		return errors.New("api key permissions must be provided")
+	}
+	return
+}
+
+// Generate replaces Prefix and KeyHash; the returned key is the only place the secret exists.
+func (k *APIKey) Generate() (result string, err error) {
+	prefix, err := randomString(6)
+	if err != nil {
+		return "", err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	result = strings.Join([]string{keyPrefix, prefix, secret}, "_")
+	k.Prefix = prefix
+	k.KeyHash = HashKey(result)
+	return
+}
+
+func (k *APIKey) Matches(key string) bool {
+	return subtle.ConstantTimeCompare([]byte(k.KeyHash), []byte(HashKey(key))) == 1
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func ParsePrefix(key string) (result string, err error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != keyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", errors.New("malformed api key")
+	}
+	return parts[1], nil
+}
+
+func randomString(size int) (result string, err error) {
+	buf := make([]byte, size)
+	_, err = rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return strings.NewReplacer("-", "", "_", "").Replace(base64.RawURLEncoding.EncodeToString(buf)), nil
+}
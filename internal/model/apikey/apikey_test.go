@@ -0,0 +1,58 @@
+package model_apikey_test
+
+import (
+	"testing"
+	"time"
+
+	model_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/model/apikey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyInstance(t *testing.T) {
+	apiKey, err := model_apikey.NewAPIKey(&model_apikey.APIKey{
+		Name:        "kiosk",
+		Permissions: []string{"rooms:read"},
+	})
+	assert.Nil(t, err)
+	key, err := apiKey.Generate()
+	assert.Nil(t, err)
+	prefix, err := model_apikey.ParsePrefix(key)
+	assert.Nil(t, err)
+	assert.Equal(t, apiKey.Prefix, prefix)
+	assert.NotContains(t, apiKey.KeyHash, key)
+	assert.True(t, apiKey.Matches(key))
+	assert.False(t, apiKey.Matches(key+"x"))
+	assert.False(t, apiKey.IsRevoked())
+}
+
+func TestAPIKeyRotation(t *testing.T) {
+	apiKey, _ := model_apikey.NewAPIKey(&model_apikey.APIKey{Name: "kiosk", Permissions: []string{"rooms:read"}})
+	oldKey, _ := apiKey.Generate()
+	newKey, _ := apiKey.Generate()
+	assert.NotEqual(t, oldKey, newKey)
+	assert.False(t, apiKey.Matches(oldKey))
+	assert.True(t, apiKey.Matches(newKey))
+}
+
+func TestAPIKeyRevoked(t *testing.T) {
+	now := time.Now()
+	apiKey := &model_apikey.APIKey{Name: "kiosk", Permissions: []string{"rooms:read"}, RevokedAt: &now}
+	assert.True(t, apiKey.IsRevoked())
+}
+
+func TestFailAPIKeyInstanceWithoutName(t *testing.T) {
+	apiKey, err := model_apikey.NewAPIKey(&model_apikey.APIKey{Permissions: []string{"rooms:read"}})
+	assert.Nil(t, apiKey)
+	assert.EqualError(t, err, "api key name must be provided")
+}
+
+func TestFailAPIKeyInstanceWithoutPermissions(t *testing.T) {
+	apiKey, err := model_apikey.NewAPIKey(&model_apikey.APIKey{Name: "kiosk"})
+	assert.Nil(t, apiKey)
+	assert.EqualError(t, err, "api key permissions must be provided")
+}
+
+func TestFailParseMalformedKey(t *testing.T) {
+	_, err := model_apikey.ParsePrefix("not-a-key")
+	assert.EqualError(t, err, "malformed api key")
+}
@@ -0,0 +1,102 @@
+package model_audit
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type Entry struct {
+	Id        string
+	Actor     string
+	Action    string
+	Entity    string
+	EntityId  string
+	Changes   map[string]Change
+	CreatedAt time.Time
+}
+
+type Filter struct {
+	Entity string
+	Actor  string
+	From   time.Time
+	To     time.Time
+}
+
+func NewEntry(e *Entry, before, after any) (result *Entry, err error) {
+	result = e
+	result.Changes, err = Diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+	err = result.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (e *Entry) IsValid() (err error) {
+	if e.Actor == "" {
+		return errors.New("audit actor must be provided")
+	}
+	if e.Action != ActionCreate && e.Action != ActionUpdate && e.Action != ActionDelete {
+		return errors.New("audit action must be create, update or delete")
+	}
+	if e.Entity == "" || e.EntityId == "" {
+		return errors.New("audit entity must be provided")
+	}
+	return
+}
+
+// Diff compares the JSON form of both snapshots and keeps only the fields that changed; nil stands for "did not exist".
+func Diff(before, after any) (result map[string]Change, err error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+	result = map[string]Change{}
+	for name, value := range beforeFields {
+		if other, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, other) {
+			result[name] = Change{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			result[name] = Change{After: value}
+		}
+	}
+	return
+}
+
+func fields(snapshot any) (result map[string]any, err error) {
+	result = map[string]any{}
+	if snapshot == nil {
+		return
+	}
+	if value := reflect.ValueOf(snapshot); value.Kind() == reflect.Pointer && value.IsNil() {
+		return
+	}
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(content, &result)
+	return
+}
@@ -0,0 +1,59 @@
+package model_audit_test
+
+import (
+	"testing"
+
+	model_audit "github.com/rochaeduardo997/irede_golang_dev/internal/model/audit"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceMovie() *model_movie.Movie {
+	return &model_movie.Movie{Id: "id", Name: "name", Director: "director", Year: 1999, DurationInSeconds: 3600}
+}
+
+func TestDiffOnCreate(t *testing.T) {
+	var before *model_movie.Movie
+	result, err := model_audit.Diff(before, instanceMovie())
+	assert.Nil(t, err)
+	assert.Equal(t, model_audit.Change{After: "name"}, result["Name"])
+	assert.Len(t, result, 5)
+}
+
+func TestDiffOnUpdate(t *testing.T) {
+	before := instanceMovie()
+	after := instanceMovie()
+	after.Name = "new name"
+	result, err := model_audit.Diff(before, after)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]model_audit.Change{"Name": {Before: "name", After: "new name"}}, result)
+}
+
+func TestDiffOnDelete(t *testing.T) {
+	var after *model_movie.Movie
+	result, err := model_audit.Diff(instanceMovie(), after)
+	assert.Nil(t, err)
+	assert.Equal(t, model_audit.Change{Before: "director"}, result["Director"])
+}
+
+func TestEntryInstance(t *testing.T) {
+	entry, err := model_audit.NewEntry(&model_audit.Entry{
+		Actor:    "user-id",
+		Action:   model_audit.ActionCreate,
+		Entity:   "movie",
+		EntityId: "id",
+	}, nil, instanceMovie())
+	assert.Nil(t, err)
+	assert.Len(t, entry.Changes, 5)
+}
+
+func TestFailEntryInstanceWithInvalidAction(t *testing.T) {
+	entry, err := model_audit.NewEntry(&model_audit.Entry{
+		Actor:    "user-id",
+		Action:   "read",
+		Entity:   "movie",
+		EntityId: "id",
+	}, nil, instanceMovie())
+	assert.Nil(t, entry)
+	assert.EqualError(t, err, "audit action must be create, update or delete")
+}
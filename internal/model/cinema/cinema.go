@@ -0,0 +1,25 @@
+package model_cinema
+
+import "errors"
+
+type Cinema struct {
+	Id   string
+	Name string
+	City string
+}
+
+func NewCinema(c *Cinema) (result *Cinema, err error) {
+	result = c
+	err = result.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (c *Cinema) IsValid() (err error) {
+	if c.Name == "" {
+		return errors.New("cinema name must be provided")
+	}
+	return
+}
@@ -0,0 +1,21 @@
+package model_cinema_test
+
+import (
+	"testing"
+
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCinemaInstance(t *testing.T) {
+	expected := &model_cinema.Cinema{Id: "id", Name: "name", City: "city"}
+	cinema, err := model_cinema.NewCinema(expected)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, cinema)
+}
+
+func TestFailCinemaInstanceWithoutName(t *testing.T) {
+	cinema, err := model_cinema.NewCinema(&model_cinema.Cinema{Id: "id", City: "city"})
+	assert.Nil(t, cinema)
+	assert.EqualError(t, err, "cinema name must be provided")
+}
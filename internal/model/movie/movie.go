@@ -2,6 +2,9 @@ package model_movie
 
 import (
 	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -9,6 +12,7 @@ type Movie struct {
 	Id                string
 	Name              string
 	Director          string
+	Year              uint16
 	DurationInSeconds uint16
 }
 
@@ -31,6 +35,9 @@ func (m *Movie) IsValid() (err error) {
 	if m.DurationInSeconds == 0 {
 		return errors.New("movie duration must be provided")
 	}
+	if m.Year != 0 && (m.Year < 1888 || m.Year > 2100) {
+		return errors.New("movie year must be between 1888 and 2100")
+	}
 	return
 }
 
@@ -39,3 +46,27 @@ func (m *Movie) DurationInHours() (result string) {
 	t = t.Add(time.Duration(m.DurationInSeconds) * time.Second)
 	return t.Format("15:04:05")
 }
+
+// ParseDuration accepts either whole seconds or the HH:MM:SS form produced by DurationInHours.
+func ParseDuration(value string) (result uint16, err error) {
+	value = strings.TrimSpace(value)
+	seconds, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		parts := strings.Split(value, ":")
+		if len(parts) != 3 {
+			return 0, errors.New("movie duration must be seconds or HH:MM:SS")
+		}
+		seconds = 0
+		for i, part := range parts {
+			n, partErr := strconv.ParseUint(part, 10, 64)
+			if partErr != nil || (i > 0 && n > 59) {
+				return 0, errors.New("movie duration must be seconds or HH:MM:SS")
+			}
+			seconds = seconds*60 + n
+		}
+	}
+	if seconds > math.MaxUint16 {
+		return 0, errors.New("movie duration must be at most 65535 seconds")
+	}
+	return uint16(seconds), nil
+}
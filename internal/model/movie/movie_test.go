@@ -59,3 +59,33 @@ func TestFailMovieInstanceWithoutDuration(t *testing.T) {
 	assert.Nil(t, movie)
 	assert.EqualError(t, err, "movie duration must be provided")
 }
+
+func TestFailMovieInstanceWithInvalidYear(t *testing.T) {
+	movie, err := model_movie.NewMovie(&model_movie.Movie{
+		Id:                "id",
+		Name:              "name",
+		Director:          "director",
+		Year:              1500,
+		DurationInSeconds: 3600,
+	})
+	assert.Nil(t, movie)
+	assert.EqualError(t, err, "movie year must be between 1888 and 2100")
+}
+
+func TestParseDuration(t *testing.T) {
+	result, err := model_movie.ParseDuration("5400")
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(5400), result)
+	result, err = model_movie.ParseDuration("01:30:00")
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(5400), result)
+}
+
+func TestFailParseDuration(t *testing.T) {
+	_, err := model_movie.ParseDuration("1h30")
+	assert.EqualError(t, err, "movie duration must be seconds or HH:MM:SS")
+	_, err = model_movie.ParseDuration("01:75:00")
+	assert.EqualError(t, err, "movie duration must be seconds or HH:MM:SS")
+	_, err = model_movie.ParseDuration("20:00:00")
+	assert.EqualError(t, err, "movie duration must be at most 65535 seconds")
+}
@@ -8,6 +8,7 @@ import (
 
 type Room struct {
 	Id          string
+	CinemaId    string
 	Number      uint16
 	Description string
 	Movies      []*model_movie.Movie
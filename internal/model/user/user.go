@@ -0,0 +1,66 @@
+package model_user
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type User struct {
+	Id           string
+	Username     string
+	Password     string
+	PasswordHash string
+	Roles        []string
+	CinemaId     string
+	Disabled     bool
+}
+
+// Unknown stands in for a username that does not exist at login, so the answer costs one bcrypt comparison either way
+// and response times do not reveal which accounts exist. Its hash is fixed and at bcrypt.DefaultCost.
+var Unknown = &User{PasswordHash: "$2a$10$KWI75Tkzerj4oTfN0qARLukuDsvLW2Tpa2e1dr5QjJLyVkD.4ZctS"}
+
+func NewUser(u *User) (result *User, err error) {
+	result = u
+	err = result.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	if result.Password != "" {
+		err = result.SetPassword(result.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if result.PasswordHash == "" {
+		return nil, errors.New("user password must be provided")
+	}
+	return
+}
+
+func (u *User) IsValid() (err error) {
+	if u.Username == "" {
+		return errors.New("user username must be provided")
+	}
+	if len(u.Roles) == 0 {
+		return errors.New("user roles must be provided")
+	}
+	return
+}
+
+func (u *User) SetPassword(password string) (err error) {
+	if len(password) < 8 {
+		return errors.New("user password must have at least 8 characters")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.Password = ""
+	u.PasswordHash = string(hash)
+	return
+}
+
+func (u *User) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
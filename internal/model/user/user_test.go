@@ -0,0 +1,73 @@
+package model_user_test
+
+import (
+	"testing"
+
+	model_user "github.com/rochaeduardo997/irede_golang_dev/internal/model/user"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUserInstance(t *testing.T) {
+	user, err := model_user.NewUser(&model_user.User{
+		Id:       "id",
+		Username: "username",
+		Password: "password",
+		Roles:    []string{"admin"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "", user.Password)
+	assert.NotEqual(t, "password", user.PasswordHash)
+	assert.True(t, user.CheckPassword("password"))
+	assert.False(t, user.CheckPassword("wrong_password"))
+}
+
+func TestUnknownCostsAsMuchAsAUser(t *testing.T) {
+	user, _ := model_user.NewUser(&model_user.User{Username: "username", Password: "password", Roles: []string{"admin"}})
+	expected, _ := bcrypt.Cost([]byte(user.PasswordHash))
+	actual, err := bcrypt.Cost([]byte(model_user.Unknown.PasswordHash))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+	assert.False(t, model_user.Unknown.CheckPassword("password"))
+}
+
+func TestFailUserInstanceWithoutUsername(t *testing.T) {
+	user, err := model_user.NewUser(&model_user.User{
+		Id:       "id",
+		Password: "password",
+		Roles:    []string{"admin"},
+	})
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "user username must be provided")
+}
+
+func TestFailUserInstanceWithoutRoles(t *testing.T) {
+	user, err := model_user.NewUser(&model_user.User{
+		Id:       "id",
+		Username: "username",
+		Password: "password",
+	})
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "user roles must be provided")
+}
+
+func TestFailUserInstanceWithoutPassword(t *testing.T) {
+	user, err := model_user.NewUser(&model_user.User{
+		Id:       "id",
+		Username: "username",
+		Roles:    []string{"admin"},
+	})
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "user password must be provided")
+}
+
+func TestFailUserInstanceWithShortPassword(t *testing.T) {
+	user, err := model_user.NewUser(&model_user.User{
+		Id:       "id",
+		Username: "username",
+		Password: "short",
+		Roles:    []string{"admin"},
+	})
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "user password must have at least 8 characters")
+}
@@ -0,0 +1,361 @@
+package view_apikey
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/model/apikey"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+type ViewAPIKey struct {
+	HTTPAdapter      http_adapter.IHTTP
+	Logger           *slog.Logger
+	Middlewares      []http_adapter.Middleware
+	Policy           *auth.Policy
+	ControllerAPIKey controller_interfaces.IAPIKeyController
+}
+
+type Body struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	RoomIds     []string `json:"roomIds"`
+	CinemaId    string   `json:"cinemaId"`
+}
+
+type Response struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Permissions []string   `json:"permissions"`
+	RoomIds     []string   `json:"roomIds"`
+	CinemaId    string     `json:"cinemaId,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	RevokedAt   *time.Time `json:"revokedAt"`
+}
+
+type KeyResponse struct {
+	Id  string `json:"id"`
+	Key string `json:"key"`
+}
+
+type FindAll struct {
+	Total     uint32      `json:"total"`
+	Page      uint16      `json:"page"`
+	Registers []*Response `json:"registers"`
+}
+
+func NewViewAPIKey(vk *ViewAPIKey) (result *ViewAPIKey) {
+	result = vk
+	if result.Logger == nil {
+		result.Logger = slog.Default()
+	}
+
+	group := result.HTTPAdapter.Group("/api/v1/apikeys", vk.Middlewares...)
+	group.AddRoute("post", "", vk.CreateHandler, vk.Policy.Require(auth.APIKeysWrite))
+	group.AddRoute("get", "/{id}", vk.FindByIdHandler, vk.Policy.Require(auth.APIKeysRead))
+	group.AddRoute("get", "/all/{page}", vk.FindAllHandler, vk.Policy.Require(auth.APIKeysRead))
+	group.AddRoute("put", "/{id}", vk.UpdateByIdHandler, vk.Policy.Require(auth.APIKeysWrite))
+	group.AddRoute("delete", "/{id}", vk.DeleteByIdHandler, vk.Policy.Require(auth.APIKeysWrite))
+	group.AddRoute("put", "/{id}/revoke", vk.RevokeHandler, vk.Policy.Require(auth.APIKeysWrite))
+	group.AddRoute("post", "/{id}/rotate", vk.RotateHandler, vk.Policy.Require(auth.APIKeysWrite))
+
+	return
+}
+
+// @Summary      Create an API key
+// @Description  The plaintext key is only returned here; only its hash is stored.
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        data body Body true "body"
+// @Success      201  {object} KeyResponse
+// @Failure      401  {string} string "missing or invalid credentials"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /apikeys [post]
+func (vk *ViewAPIKey) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	input := &Body{}
+	if !http_adapter.DecodeJSON(w, r, input) {
+		return
+	}
+	apiKey, err := model_apikey.NewAPIKey(&model_apikey.APIKey{Name: input.Name, Permissions: input.Permissions, RoomIds: input.RoomIds, CinemaId: input.CinemaId})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	apiKey.CinemaId, err = tenant.Resolve(r.Context(), apiKey.CinemaId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if !vk.canGrant(w, r, apiKey) {
+		return
+	}
+	key, err := apiKey.Generate()
+	if err != nil {
+		vk.Logger.ErrorContext(r.Context(), "api key generation failed", "err", err)
+		http.Error(w, "api key could not be generated", http.StatusInternalServerError)
+		return
+	}
+	result, err := vk.ControllerAPIKey.Create(r.Context(), apiKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resJSON, err := json.Marshal(&KeyResponse{Id: result, Key: key})
+	if err != nil {
+		vk.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(resJSON)
+}
+
+// @Summary      Get API key by id
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "API key ID"
+// @Success      200  {object} Response
+// @Failure      401  {string} string "missing or invalid credentials"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /apikeys/{id} [get]
+func (vk *ViewAPIKey) FindByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vk.HTTPAdapter.Param(r, "id")
+	if id == "" {
+		http.Error(w, "id must be provided", http.StatusBadRequest)
+		return
+	}
+	result, err := vk.ControllerAPIKey.FindBy(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resJSON, err := json.Marshal(apiKeyResponse(result))
+	if err != nil {
+		vk.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
+
+// @Summary      Get all API keys
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        page   path      string true  "Page"
+// @Success      200  {object}    FindAll
+// @Failure      401  {string} string "missing or invalid credentials"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /apikeys/all/{page} [get]
+func (vk *ViewAPIKey) FindAllHandler(w http.ResponseWriter, r *http.Request) {
+	page := vk.HTTPAdapter.Param(r, "page")
+	pageInt, err := strconv.Atoi(page)
+	if err != nil {
+		http.Error(w, "page must be provided", http.StatusBadRequest)
+		return
+	}
+	result, err := vk.ControllerAPIKey.FindAll(r.Context(), uint16(pageInt))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := &FindAll{Total: result.Total, Page: result.Page, Registers: []*Response{}}
+	for _, apiKey := range result.Registers {
+		res.Registers = append(res.Registers, apiKeyResponse(apiKey))
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		vk.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
+
+// @Summary      Update API key name and scopes
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "API key ID"
+// @Param        data body Body true "body"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid credentials"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /apikeys/{id} [put]
+func (vk *ViewAPIKey) UpdateByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vk.HTTPAdapter.Param(r, "id")
+	input := &Body{}
+	if !http_adapter.DecodeJSON(w, r, input) {
+		return
+	}
+	apiKey := &model_apikey.APIKey{Id: id, Name: input.Name, Permissions: input.Permissions, RoomIds: input.RoomIds, CinemaId: input.CinemaId}
+	err := apiKey.IsValid()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	apiKey.CinemaId, err = tenant.Resolve(r.Context(), apiKey.CinemaId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if !vk.canManage(w, r, id) || !vk.canGrant(w, r, apiKey) {
+		return
+	}
+	result, err := vk.ControllerAPIKey.UpdateBy(r.Context(), id, apiKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+// @Summary      Delete an API key by id
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "API key ID"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid credentials"
+// @Failure      403  {string} string "missing permission or the key reaches further than the caller"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /apikeys/{id} [delete]
+func (vk *ViewAPIKey) DeleteByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vk.HTTPAdapter.Param(r, "id")
+	if id == "" {
+		http.Error(w, "id must be provided", http.StatusBadRequest)
+		return
+	}
+	if !vk.canManage(w, r, id) {
+		return
+	}
+	result, err := vk.ControllerAPIKey.DeleteBy(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+// @Summary      Revoke an API key
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "API key ID"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid credentials"
+// @Failure      403  {string} string "missing permission or the key reaches further than the caller"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /apikeys/{id}/revoke [put]
+func (vk *ViewAPIKey) RevokeHandler(w http.ResponseWriter, r *http.Request) {
+	id := vk.HTTPAdapter.Param(r, "id")
+	if !vk.canManage(w, r, id) {
+		return
+	}
+	result, err := vk.ControllerAPIKey.Revoke(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+// @Summary      Rotate an API key
+// @Description  Issues a new secret for the key; the previous secret stops working immediately.
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "API key ID"
+// @Success      200  {object} KeyResponse
+// @Failure      401  {string} string "missing or invalid credentials"
+// @Failure      403  {string} string "missing permission or the key reaches further than the caller"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /apikeys/{id}/rotate [post]
+func (vk *ViewAPIKey) RotateHandler(w http.ResponseWriter, r *http.Request) {
+	id := vk.HTTPAdapter.Param(r, "id")
+	if !vk.canManage(w, r, id) {
+		return
+	}
+	key, err := vk.ControllerAPIKey.Rotate(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resJSON, err := json.Marshal(&KeyResponse{Id: id, Key: key})
+	if err != nil {
+		vk.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
+
+// canGrant stops callers from minting keys with permissions they do not hold themselves, and room-scoped callers
+// from minting keys reaching rooms outside their own, an empty RoomIds being every room.
+func (vk *ViewAPIKey) canGrant(w http.ResponseWriter, r *http.Request, apiKey *model_apikey.APIKey) bool {
+	principal := auth.PrincipalFrom(r.Context())
+	for _, permission := range apiKey.Permissions {
+		if !vk.Policy.Allows(principal, permission) {
+			http.Error(w, "cannot grant permission "+permission, http.StatusForbidden)
+			return false
+		}
+	}
+	if principal == nil || len(principal.RoomIds) == 0 {
+		return true
+	}
+	if len(apiKey.RoomIds) == 0 {
+		http.Error(w, "room-scoped credentials must restrict the key to their rooms", http.StatusForbidden)
+		return false
+	}
+	for _, roomId := range apiKey.RoomIds {
+		if !principal.AllowsRoom(roomId) {
+			http.Error(w, "cannot grant room "+roomId, http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
+// canManage applies canGrant to the stored key, so callers cannot update, rotate, revoke or delete a key reaching
+// further than themselves.
+func (vk *ViewAPIKey) canManage(w http.ResponseWriter, r *http.Request, id string) bool {
+	apiKey, err := vk.ControllerAPIKey.FindBy(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return vk.canGrant(w, r, apiKey)
+}
+
+func apiKeyResponse(apiKey *model_apikey.APIKey) (result *Response) {
+	return &Response{
+		Id:          apiKey.Id,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		Permissions: apiKey.Permissions,
+		RoomIds:     apiKey.RoomIds,
+		CinemaId:    apiKey.CinemaId,
+		CreatedAt:   apiKey.CreatedAt,
+		LastUsedAt:  apiKey.LastUsedAt,
+		RevokedAt:   apiKey.RevokedAt,
+	}
+}
@@ -0,0 +1,244 @@
+package view_apikey_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controller_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/controller/apikey"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	model_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/model/apikey"
+	view_apikey "github.com/rochaeduardo997/irede_golang_dev/internal/view/apikey"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	return
+}
+
+func instanceControllerAPIKey(db *sql.DB) (result controller_interfaces.IAPIKeyController) {
+	result, _ = controller_apikey.NewControllerAPIKey(&controller_apikey.ControllerAPIKey{Db: db})
+	return
+}
+
+// withPrincipal stands in for the authenticator so the policy checks have someone to look at.
+func withPrincipal(principal *auth.Principal) http_adapter.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+func instanceView(ck controller_interfaces.IAPIKeyController, principal *auth.Principal, newAdapter http_adapter.Factory) http.Handler {
+	policy, _ := auth.LoadPolicy("")
+	httpAdapter, handler := newAdapter()
+	view_apikey.NewViewAPIKey(&view_apikey.ViewAPIKey{
+		HTTPAdapter:      httpAdapter,
+		Middlewares:      []http_adapter.Middleware{withPrincipal(principal)},
+		Policy:           policy,
+		ControllerAPIKey: ck,
+	})
+	return handler
+}
+
+func forEachAdapter(t *testing.T, test func(t *testing.T, newAdapter http_adapter.Factory)) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			test(t, newAdapter)
+		})
+	}
+}
+
+func createKey(t *testing.T, url string, body map[string]any) (result *view_apikey.KeyResponse, status int) {
+	bodyJSON, _ := json.Marshal(body)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(bodyJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := io.ReadAll(resp.Body)
+	result = &view_apikey.KeyResponse{}
+	json.Unmarshal(actual, result)
+	return result, resp.StatusCode
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testInsert)
+}
+
+func testInsert(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	ck := instanceControllerAPIKey(db)
+	server := httptest.NewServer(instanceView(ck, &auth.Principal{Roles: []string{"admin"}}, newAdapter))
+	defer server.Close()
+
+	body := map[string]any{"name": "kiosk", "permissions": []string{"rooms:read"}, "roomIds": []string{"room-a"}}
+	result, status := createKey(t, fmt.Sprintf("%s/api/v1/apikeys", server.URL), body)
+	assert.Equal(t, http.StatusCreated, status)
+	apiKey, err := ck.FindBy(context.Background(), result.Id)
+	assert.Nil(t, err)
+	assert.True(t, apiKey.Matches(result.Key))
+	assert.Equal(t, []string{"room-a"}, apiKey.RoomIds)
+}
+
+func TestFailInsertWithPermissionNotHeld(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFailInsertWithPermissionNotHeld)
+}
+
+func testFailInsertWithPermissionNotHeld(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	ck := instanceControllerAPIKey(db)
+	principal := &auth.Principal{Permissions: []string{auth.APIKeysWrite, auth.RoomsRead}}
+	server := httptest.NewServer(instanceView(ck, principal, newAdapter))
+	defer server.Close()
+
+	body := map[string]any{"name": "kiosk", "permissions": []string{"rooms:write"}}
+	_, status := createKey(t, fmt.Sprintf("%s/api/v1/apikeys", server.URL), body)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestFailInsertOutsideRoomScope(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFailInsertOutsideRoomScope)
+}
+
+func testFailInsertOutsideRoomScope(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	ck := instanceControllerAPIKey(db)
+	principal := &auth.Principal{Permissions: []string{auth.APIKeysWrite, auth.RoomsRead}, RoomIds: []string{"room-a", "room-b"}}
+	server := httptest.NewServer(instanceView(ck, principal, newAdapter))
+	defer server.Close()
+	url := fmt.Sprintf("%s/api/v1/apikeys", server.URL)
+
+	_, status := createKey(t, url, map[string]any{"name": "kiosk", "permissions": []string{"rooms:read"}})
+	assert.Equal(t, http.StatusForbidden, status)
+	_, status = createKey(t, url, map[string]any{"name": "kiosk", "permissions": []string{"rooms:read"}, "roomIds": []string{"room-a", "room-c"}})
+	assert.Equal(t, http.StatusForbidden, status)
+	result, status := createKey(t, url, map[string]any{"name": "kiosk", "permissions": []string{"rooms:read"}, "roomIds": []string{"room-b"}})
+	assert.Equal(t, http.StatusCreated, status)
+
+	bodyJSON, _ := json.Marshal(map[string]any{"name": "kiosk", "permissions": []string{"rooms:read"}})
+	req, _ := http.NewRequest(http.MethodPut, url+"/"+result.Id, bytes.NewBuffer(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	apiKey, _ := ck.FindBy(context.Background(), result.Id)
+	assert.Equal(t, []string{"room-b"}, apiKey.RoomIds)
+}
+
+func TestFindById(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindById)
+}
+
+func testFindById(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	ck := instanceControllerAPIKey(db)
+	server := httptest.NewServer(instanceView(ck, &auth.Principal{Roles: []string{"admin"}}, newAdapter))
+	defer server.Close()
+
+	created, _ := createKey(t, fmt.Sprintf("%s/api/v1/apikeys", server.URL), map[string]any{"name": "kiosk", "permissions": []string{"rooms:read"}})
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/apikeys/%s", server.URL, created.Id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := io.ReadAll(resp.Body)
+	bodyRes := &view_apikey.Response{}
+	json.Unmarshal(actual, bodyRes)
+	assert.Equal(t, created.Id, bodyRes.Id)
+	assert.Equal(t, "kiosk", bodyRes.Name)
+	assert.NotContains(t, string(actual), created.Key)
+}
+
+func TestRevokeAndRotate(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testRevokeAndRotate)
+}
+
+func testRevokeAndRotate(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	ck := instanceControllerAPIKey(db)
+	server := httptest.NewServer(instanceView(ck, &auth.Principal{Roles: []string{"admin"}}, newAdapter))
+	defer server.Close()
+
+	created, _ := createKey(t, fmt.Sprintf("%s/api/v1/apikeys", server.URL), map[string]any{"name": "kiosk", "permissions": []string{"rooms:read"}})
+	resp, err := http.Post(fmt.Sprintf("%s/api/v1/apikeys/%s/rotate", server.URL, created.Id), "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := io.ReadAll(resp.Body)
+	rotated := &view_apikey.KeyResponse{}
+	json.Unmarshal(actual, rotated)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, created.Key, rotated.Key)
+
+	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v1/apikeys/%s/revoke", server.URL, created.Id), nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	apiKey, _ := ck.FindBy(context.Background(), created.Id)
+	assert.True(t, apiKey.IsRevoked())
+}
+
+func TestFailManageBroaderKey(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFailManageBroaderKey)
+}
+
+func testFailManageBroaderKey(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	ck := instanceControllerAPIKey(db)
+	apiKey, _ := model_apikey.NewAPIKey(&model_apikey.APIKey{Name: "admin", Permissions: []string{"*"}})
+	key, _ := apiKey.Generate()
+	id, err := ck.Create(context.Background(), apiKey)
+	assert.Nil(t, err)
+
+	principals := []*auth.Principal{
+		{Permissions: []string{auth.APIKeysWrite, auth.RoomsRead}, RoomIds: []string{"room-a"}},
+		{Permissions: []string{auth.APIKeysWrite}},
+	}
+	for _, principal := range principals {
+		server := httptest.NewServer(instanceView(ck, principal, newAdapter))
+		for _, request := range []struct{ method, path string }{
+			{http.MethodPost, "/rotate"},
+			{http.MethodPut, "/revoke"},
+			{http.MethodDelete, ""},
+		} {
+			req, _ := http.NewRequest(request.method, fmt.Sprintf("%s/api/v1/apikeys/%s%s", server.URL, id, request.path), nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, request.method+request.path)
+			assert.NotContains(t, string(actual), `"key"`)
+		}
+		server.Close()
+	}
+	stored, err := ck.FindBy(context.Background(), id)
+	assert.Nil(t, err)
+	assert.False(t, stored.IsRevoked())
+	found, _ := ck.FindByPrefix(context.Background(), stored.Prefix)
+	assert.NotNil(t, found)
+	assert.True(t, found.Matches(key))
+}
@@ -0,0 +1,117 @@
+package view_audit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	model_audit "github.com/rochaeduardo997/irede_golang_dev/internal/model/audit"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+type ViewAudit struct {
+	HTTPAdapter     http_adapter.IHTTP
+	Logger          *slog.Logger
+	Middlewares     []http_adapter.Middleware
+	Policy          *auth.Policy
+	ControllerAudit controller_interfaces.IAuditController
+}
+
+type Response struct {
+	Id        string                        `json:"id"`
+	Actor     string                        `json:"actor"`
+	Action    string                        `json:"action"`
+	Entity    string                        `json:"entity"`
+	EntityId  string                        `json:"entityId"`
+	Changes   map[string]model_audit.Change `json:"changes"`
+	CreatedAt time.Time                     `json:"createdAt"`
+}
+
+type FindAll struct {
+	Total     uint32      `json:"total"`
+	Page      uint16      `json:"page"`
+	Registers []*Response `json:"registers"`
+}
+
+func NewViewAudit(va *ViewAudit) (result *ViewAudit) {
+	result = va
+	if result.Logger == nil {
+		result.Logger = slog.Default()
+	}
+
+	group := result.HTTPAdapter.Group("/api/v1/audit", va.Middlewares...)
+	group.AddRoute("get", "", va.FindAllHandler, va.Policy.Require(auth.AuditRead))
+
+	return
+}
+
+// @Summary      List audit entries
+// @Description  Newest first. from and to are RFC 3339 timestamps.
+// @Tags         Audit
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        entity query     string false "Entity (movie or room)"
+// @Param        actor  query     string false "Actor (user id or apikey:<id>)"
+// @Param        from   query     string false "Start of the time range"
+// @Param        to     query     string false "End of the time range"
+// @Param        page   query     int    false "Page" default(1)
+// @Success      200  {object}    FindAll
+// @Failure      400  {string} string "invalid filter"
+// @Failure      401  {string} string "missing or invalid credentials"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /audit [get]
+func (va *ViewAudit) FindAllHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &model_audit.Filter{Entity: query.Get("entity"), Actor: query.Get("actor")}
+	var err error
+	if from := query.Get("from"); from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			http.Error(w, "from must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			http.Error(w, "to must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+	page := 1
+	if value := query.Get("page"); value != "" {
+		page, err = strconv.Atoi(value)
+		if err != nil || page < 1 {
+			http.Error(w, "page must be a positive number", http.StatusBadRequest)
+			return
+		}
+	}
+	result, err := va.ControllerAudit.FindAll(r.Context(), filter, uint16(page))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := &FindAll{Total: result.Total, Page: result.Page, Registers: []*Response{}}
+	for _, entry := range result.Registers {
+		res.Registers = append(res.Registers, &Response{
+			Id:        entry.Id,
+			Actor:     entry.Actor,
+			Action:    entry.Action,
+			Entity:    entry.Entity,
+			EntityId:  entry.EntityId,
+			Changes:   entry.Changes,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		va.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
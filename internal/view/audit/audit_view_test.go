@@ -0,0 +1,92 @@
+package view_audit_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controller_audit "github.com/rochaeduardo997/irede_golang_dev/internal/controller/audit"
+	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	view_audit "github.com/rochaeduardo997/irede_golang_dev/internal/view/audit"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	return
+}
+
+func forEachAdapter(t *testing.T, test func(t *testing.T, newAdapter http_adapter.Factory)) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			test(t, newAdapter)
+		})
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindAll)
+}
+
+func testFindAll(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm, _ := controller_movie.NewControllerMovie(&controller_movie.ControllerMovie{Db: db})
+	ca, _ := controller_audit.NewControllerAudit(&controller_audit.ControllerAudit{Db: db})
+	movie, _ := model_movie.NewMovie(&model_movie.Movie{Name: "name", Director: "director", DurationInSeconds: 3600})
+	id, _ := cm.Create(context.Background(), movie)
+
+	httpAdapter, handler := newAdapter()
+	view_audit.NewViewAudit(&view_audit.ViewAudit{HTTPAdapter: httpAdapter, ControllerAudit: ca})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/audit?entity=movie", server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	bodyRes := &view_audit.FindAll{}
+	json.Unmarshal(actual, bodyRes)
+	assert.Equal(t, uint32(1), bodyRes.Total)
+	assert.Equal(t, id, bodyRes.Registers[0].EntityId)
+	assert.Equal(t, "create", bodyRes.Registers[0].Action)
+
+	resp, err = http.Get(fmt.Sprintf("%s/api/v1/audit?entity=room", server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ = io.ReadAll(resp.Body)
+	json.Unmarshal(actual, bodyRes)
+	assert.Equal(t, uint32(0), bodyRes.Total)
+}
+
+func TestFailFindAllWithInvalidTime(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFailFindAllWithInvalidTime)
+}
+
+func testFailFindAllWithInvalidTime(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	ca, _ := controller_audit.NewControllerAudit(&controller_audit.ControllerAudit{Db: db})
+	httpAdapter, handler := newAdapter()
+	view_audit.NewViewAudit(&view_audit.ViewAudit{HTTPAdapter: httpAdapter, ControllerAudit: ca})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/audit?from=yesterday", server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,217 @@
+package view_bulk
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+const (
+	ModeAtomic  = "atomic"
+	ModePartial = "partial"
+	MaxItems    = 500
+)
+
+type Item struct {
+	Index  int    `json:"index"`
+	Id     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Mode      string  `json:"mode"`
+	Succeeded int     `json:"succeeded"`
+	Failed    int     `json:"failed"`
+	Items     []*Item `json:"items"`
+}
+
+type Batch struct {
+	Db    *sql.DB
+	Mode  string
+	Count int
+	// Validate rejects item i before anything is written; nil accepts every item.
+	Validate func(ctx context.Context, i int) error
+	// Apply writes item i and returns the id it touched with the status reported on success.
+	Apply func(ctx context.Context, i int) (id string, status int, err error)
+}
+
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+// Fail builds an item error reported with status instead of the default 400.
+func Fail(status int, message string) error {
+	return &statusError{status: status, message: message}
+}
+
+// ParseMode reads ?mode=, defaulting to atomic.
+func ParseMode(r *http.Request) (result string, err error) {
+	result = r.URL.Query().Get("mode")
+	switch result {
+	case "":
+		return ModeAtomic, nil
+	case ModeAtomic, ModePartial:
+		return
+	}
+	return "", errors.New("mode must be atomic or partial")
+}
+
+func checkCount(count int) error {
+	if count == 0 {
+		return errors.New("items must be provided")
+	}
+	if count > MaxItems {
+		return fmt.Errorf("bulk requests accept at most %d items", MaxItems)
+	}
+	return nil
+}
+
+// Decode reads the mode and the items of a bulk request, answering 400 when either is unusable.
+func Decode(w http.ResponseWriter, r *http.Request, target any, count func() int) (mode string, ok bool) {
+	mode, err := ParseMode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	if !http_adapter.DecodeJSON(w, r, target) {
+		return "", false
+	}
+	if err = checkCount(count()); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	return mode, true
+}
+
+// Run validates every item and then applies them; atomic batches share one transaction and apply nothing unless every item succeeds.
+func (b *Batch) Run(ctx context.Context) (result *Report) {
+	result = &Report{Mode: b.Mode, Items: make([]*Item, b.Count)}
+	invalid := false
+	for i := range result.Items {
+		result.Items[i] = &Item{Index: i}
+		if b.Validate == nil {
+			continue
+		}
+		if err := b.Validate(ctx, i); err != nil {
+			fail(result.Items[i], err)
+			invalid = true
+		}
+	}
+
+	if b.Mode == ModePartial {
+		for _, item := range result.Items {
+			if item.Status == 0 {
+				b.apply(ctx, item)
+			}
+		}
+		return result.count()
+	}
+
+	if invalid {
+		notApplied(result.Items, "not applied: other items in the batch are invalid")
+		return result.count()
+	}
+	failed := -1
+	err := database.InTx(ctx, b.Db, func(ctx context.Context) error {
+		for _, item := range result.Items {
+			if err := b.apply(ctx, item); err != nil {
+				failed = item.Index
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && failed < 0 {
+		for _, item := range result.Items {
+			item.Id = ""
+			fail(item, err)
+		}
+	} else if err != nil {
+		notApplied(result.Items, fmt.Sprintf("rolled back: item %d failed", failed))
+	}
+	return result.count()
+}
+
+func (b *Batch) apply(ctx context.Context, item *Item) (err error) {
+	id, status, err := b.Apply(ctx, item.Index)
+	if err != nil {
+		fail(item, err)
+		return
+	}
+	item.Id = id
+	item.Status = status
+	return
+}
+
+func (r *Report) count() *Report {
+	for _, item := range r.Items {
+		if item.Error == "" {
+			r.Succeeded++
+		} else {
+			r.Failed++
+		}
+	}
+	return r
+}
+
+func fail(item *Item, err error) {
+	item.Status = Status(err)
+	item.Error = err.Error()
+}
+
+func notApplied(items []*Item, message string) {
+	for _, item := range items {
+		if item.Error == "" {
+			item.Id = ""
+			item.Status = http.StatusFailedDependency
+			item.Error = message
+		}
+	}
+}
+
+// Status maps an item error to the status the single-item endpoint would answer with.
+func Status(err error) int {
+	var failure *statusError
+	var conflict *controller_interfaces.ConflictError
+	switch {
+	case errors.As(err, &failure):
+		return failure.status
+	case errors.As(err, &conflict):
+		return http.StatusConflict
+	case errors.Is(err, tenant.ErrOtherCinema):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
+// WriteReport answers 200 when every item succeeded, 422 when an atomic batch was rolled back and 207 for partial failures.
+func WriteReport(w http.ResponseWriter, report *Report) error {
+	status := http.StatusOK
+	if report.Failed > 0 && report.Mode == ModeAtomic {
+		status = http.StatusUnprocessableEntity
+	} else if report.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	resJSON, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(resJSON)
+	return nil
+}
@@ -0,0 +1,82 @@
+package view_bulk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	view_bulk "github.com/rochaeduardo997/irede_golang_dev/internal/view/bulk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartialReportsEveryItem(t *testing.T) {
+	applied := []int{}
+	report := (&view_bulk.Batch{
+		Mode:  view_bulk.ModePartial,
+		Count: 4,
+		Validate: func(ctx context.Context, i int) error {
+			if i == 1 {
+				return errors.New("movie name must be provided")
+			}
+			return nil
+		},
+		Apply: func(ctx context.Context, i int) (string, int, error) {
+			if i == 2 {
+				return "", 0, &controller_interfaces.ConflictError{Entity: "movie", ExistingId: "existing"}
+			}
+			applied = append(applied, i)
+			return strconv.Itoa(i), http.StatusCreated, nil
+		},
+	}).Run(context.Background())
+
+	assert.Equal(t, []int{0, 3}, applied)
+	assert.Equal(t, 2, report.Succeeded)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, &view_bulk.Item{Index: 0, Id: "0", Status: http.StatusCreated}, report.Items[0])
+	assert.Equal(t, &view_bulk.Item{Index: 1, Status: http.StatusBadRequest, Error: "movie name must be provided"}, report.Items[1])
+	assert.Equal(t, http.StatusConflict, report.Items[2].Status)
+
+	rec := httptest.NewRecorder()
+	view_bulk.WriteReport(rec, report)
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+}
+
+func TestAtomicWithInvalidItemAppliesNothing(t *testing.T) {
+	report := (&view_bulk.Batch{
+		Mode:  view_bulk.ModeAtomic,
+		Count: 2,
+		Validate: func(ctx context.Context, i int) error {
+			if i == 1 {
+				return view_bulk.Fail(http.StatusForbidden, "room not allowed for these credentials")
+			}
+			return nil
+		},
+		Apply: func(ctx context.Context, i int) (string, int, error) {
+			t.Fatal("no item should be applied")
+			return "", 0, nil
+		},
+	}).Run(context.Background())
+
+	assert.Equal(t, 0, report.Succeeded)
+	assert.Equal(t, http.StatusFailedDependency, report.Items[0].Status)
+	assert.Equal(t, http.StatusForbidden, report.Items[1].Status)
+
+	rec := httptest.NewRecorder()
+	view_bulk.WriteReport(rec, report)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := view_bulk.ParseMode(httptest.NewRequest(http.MethodPost, "/movies/bulk", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, view_bulk.ModeAtomic, mode)
+	mode, err = view_bulk.ParseMode(httptest.NewRequest(http.MethodPost, "/movies/bulk?mode=partial", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, view_bulk.ModePartial, mode)
+	_, err = view_bulk.ParseMode(httptest.NewRequest(http.MethodPost, "/movies/bulk?mode=all", nil))
+	assert.Equal(t, "mode must be atomic or partial", err.Error())
+}
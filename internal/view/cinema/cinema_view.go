@@ -0,0 +1,212 @@
+package view_cinema
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+type ViewCinema struct {
+	HTTPAdapter      http_adapter.IHTTP
+	Logger           *slog.Logger
+	Middlewares      []http_adapter.Middleware
+	Policy           *auth.Policy
+	ControllerCinema controller_interfaces.IGenericController[model_cinema.Cinema]
+}
+
+type Body struct {
+	Name string `json:"name"`
+	City string `json:"city"`
+}
+
+type Response struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	City string `json:"city"`
+}
+
+type FindAll struct {
+	Total     uint32      `json:"total"`
+	Page      uint16      `json:"page"`
+	Registers []*Response `json:"registers"`
+}
+
+func NewViewCinema(vc *ViewCinema) (result *ViewCinema) {
+	result = vc
+	if result.Logger == nil {
+		result.Logger = slog.Default()
+	}
+
+	group := result.HTTPAdapter.Group("/api/v1/cinemas", vc.Middlewares...)
+	group.AddRoute("post", "", vc.CreateHandler, vc.Policy.Require(auth.CinemasWrite))
+	group.AddRoute("get", "/{id}", vc.FindByIdHandler, vc.Policy.Require(auth.CinemasRead))
+	group.AddRoute("get", "/all/{page}", vc.FindAllHandler, vc.Policy.Require(auth.CinemasRead))
+	group.AddRoute("put", "/{id}", vc.UpdateByIdHandler, vc.Policy.Require(auth.CinemasWrite))
+	group.AddRoute("delete", "/{id}", vc.DeleteByIdHandler, vc.Policy.Require(auth.CinemasWrite))
+
+	return
+}
+
+// @Summary      Create a cinema
+// @Tags         Cinemas
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        data body Body true "body"
+// @Success      201  {string} string true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /cinemas [post]
+func (vc *ViewCinema) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	input := &Body{}
+	if !http_adapter.DecodeJSON(w, r, input) {
+		return
+	}
+	cinema, err := model_cinema.NewCinema(&model_cinema.Cinema{Name: input.Name, City: input.City})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := vc.ControllerCinema.Create(r.Context(), cinema)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(result))
+}
+
+// @Summary      Get cinema by id
+// @Tags         Cinemas
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "Cinema ID"
+// @Success      200  {object} Response
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /cinemas/{id} [get]
+func (vc *ViewCinema) FindByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vc.HTTPAdapter.Param(r, "id")
+	if id == "" {
+		http.Error(w, "id must be provided", http.StatusBadRequest)
+		return
+	}
+	result, err := vc.ControllerCinema.FindBy(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resJSON, err := json.Marshal(cinemaResponse(result))
+	if err != nil {
+		vc.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
+
+// @Summary      Get all cinemas
+// @Tags         Cinemas
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        page   path      string true  "Page"
+// @Success      200  {object}    FindAll
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /cinemas/all/{page} [get]
+func (vc *ViewCinema) FindAllHandler(w http.ResponseWriter, r *http.Request) {
+	page := vc.HTTPAdapter.Param(r, "page")
+	pageInt, err := strconv.Atoi(page)
+	if err != nil {
+		http.Error(w, "page must be provided", http.StatusBadRequest)
+		return
+	}
+	result, err := vc.ControllerCinema.FindAll(r.Context(), uint16(pageInt))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := &FindAll{Total: result.Total, Page: result.Page, Registers: []*Response{}}
+	for _, cinema := range result.Registers {
+		res.Registers = append(res.Registers, cinemaResponse(cinema))
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		vc.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
+
+// @Summary      Update cinema by id
+// @Tags         Cinemas
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "Cinema ID"
+// @Param        data body Body true "body"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /cinemas/{id} [put]
+func (vc *ViewCinema) UpdateByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vc.HTTPAdapter.Param(r, "id")
+	input := &Body{}
+	if !http_adapter.DecodeJSON(w, r, input) {
+		return
+	}
+	cinema, err := model_cinema.NewCinema(&model_cinema.Cinema{Id: id, Name: input.Name, City: input.City})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := vc.ControllerCinema.UpdateBy(r.Context(), id, cinema)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+// @Summary      Delete a cinema by id
+// @Tags         Cinemas
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "Cinema ID"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /cinemas/{id} [delete]
+func (vc *ViewCinema) DeleteByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vc.HTTPAdapter.Param(r, "id")
+	if id == "" {
+		http.Error(w, "id must be provided", http.StatusBadRequest)
+		return
+	}
+	result, err := vc.ControllerCinema.DeleteBy(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+func cinemaResponse(cinema *model_cinema.Cinema) (result *Response) {
+	return &Response{Id: cinema.Id, Name: cinema.Name, City: cinema.City}
+}
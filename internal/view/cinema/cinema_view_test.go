@@ -0,0 +1,91 @@
+package view_cinema_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controller_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/controller/cinema"
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	model_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/model/cinema"
+	view_cinema "github.com/rochaeduardo997/irede_golang_dev/internal/view/cinema"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	return
+}
+
+func instanceControllerCinema(db *sql.DB) (result controller_interfaces.IGenericController[model_cinema.Cinema]) {
+	result, _ = controller_cinema.NewControllerCinema(&controller_cinema.ControllerCinema{Db: db})
+	return
+}
+
+func forEachAdapter(t *testing.T, test func(t *testing.T, newAdapter http_adapter.Factory)) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			test(t, newAdapter)
+		})
+	}
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testInsert)
+}
+
+func testInsert(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cc := instanceControllerCinema(db)
+	httpAdapter, handler := newAdapter()
+	view_cinema.NewViewCinema(&view_cinema.ViewCinema{HTTPAdapter: httpAdapter, ControllerCinema: cc})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	bodyJSON, _ := json.Marshal(map[string]any{"name": "name", "city": "city"})
+	resp, err := http.Post(fmt.Sprintf("%s/api/v1/cinemas", server.URL), "application/json", bytes.NewBuffer(bodyJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	cinema, err := cc.FindBy(context.Background(), string(actual))
+	assert.Nil(t, err)
+	assert.Equal(t, "name", cinema.Name)
+	assert.Equal(t, "city", cinema.City)
+}
+
+func TestFindById(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindById)
+}
+
+func testFindById(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cc := instanceControllerCinema(db)
+	id, _ := cc.Create(context.Background(), &model_cinema.Cinema{Name: "name", City: "city"})
+	httpAdapter, handler := newAdapter()
+	view_cinema.NewViewCinema(&view_cinema.ViewCinema{HTTPAdapter: httpAdapter, ControllerCinema: cc})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/cinemas/%s", server.URL, id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	bodyRes := &view_cinema.Response{}
+	json.Unmarshal(actual, bodyRes)
+	assert.Equal(t, &view_cinema.Response{Id: id, Name: "name", City: "city"}, bodyRes)
+}
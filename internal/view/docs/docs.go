@@ -9,6 +9,6 @@ type DocsView struct{ HTTPAdapter http_adapter.IHTTP }
 
 func NewDocsView(dv *DocsView) (result *DocsView, err error) {
 	result = dv
-	result.HTTPAdapter.Route().PathPrefix("/swagger").Handler(httpSwagger.WrapHandler)
+	result.HTTPAdapter.AddPrefixRoute("/swagger", httpSwagger.WrapHandler)
 	return
 }
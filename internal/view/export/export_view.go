@@ -0,0 +1,176 @@
+package view_export
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/rochaeduardo997/irede_golang_dev/pkg/tabular"
+)
+
+type ViewExport struct {
+	HTTPAdapter      http_adapter.IHTTP
+	Logger           *slog.Logger
+	Middlewares      []http_adapter.Middleware
+	Policy           *auth.Policy
+	ControllerExport controller_interfaces.IExportController
+}
+
+var (
+	movieColumns  = []string{"id", "name", "director", "year", "durationInSeconds", "durationInHours"}
+	roomColumns   = []string{"id", "cinemaId", "number", "description"}
+	lineupColumns = []string{"roomId", "cinemaId", "roomNumber", "movieId", "movieName", "director", "year", "durationInSeconds", "durationInHours"}
+)
+
+func NewViewExport(ve *ViewExport) (result *ViewExport) {
+	result = ve
+	if result.Logger == nil {
+		result.Logger = slog.Default()
+	}
+
+	group := result.HTTPAdapter.Group("/api/v1/export", ve.Middlewares...)
+	group.AddRoute("get", "/movies", ve.MoviesHandler, ve.Policy.Require(auth.MoviesRead))
+	group.AddRoute("get", "/rooms", ve.RoomsHandler, ve.Policy.Require(auth.RoomsRead))
+	group.AddRoute("get", "/lineups", ve.LineupsHandler, ve.Policy.Require(auth.RoomsRead), ve.Policy.Require(auth.MoviesRead))
+
+	return
+}
+
+// @Summary      Export all movies
+// @Description  Same filters as the movie listings.
+// @Tags         Export
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format query string false "File format" Enums(csv, ndjson, xlsx) default(csv)
+// @Param        roomId query string false "Only movies shown in this room"
+// @Success      200  {file} file
+// @Failure      400  {string} string "format must be csv, ndjson or xlsx"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission or room not allowed for these credentials"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /export/movies [get]
+func (ve *ViewExport) MoviesHandler(w http.ResponseWriter, r *http.Request) {
+	roomId := r.URL.Query().Get("roomId")
+	if roomId != "" && !auth.PrincipalFrom(r.Context()).AllowsRoom(roomId) {
+		http.Error(w, "room not allowed for these credentials", http.StatusForbidden)
+		return
+	}
+	ctx := controller_interfaces.WithRoom(r.Context(), roomId)
+	ve.stream(w, r, "movies", movieColumns, func(tw tabular.Writer) error {
+		return ve.ControllerExport.Movies(ctx, func(m *model_movie.Movie) error {
+			return tw.Row(m.Id, m.Name, m.Director, year(m), m.DurationInSeconds, m.DurationInHours())
+		})
+	})
+}
+
+// @Summary      Export all rooms
+// @Description  Same scope as the room listings: the caller's cinema, optionally narrowed by cinemaId and movieId.
+// @Tags         Export
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format   query string false "File format" Enums(csv, ndjson, xlsx) default(csv)
+// @Param        cinemaId query string false "Cinema ID"
+// @Param        movieId  query string false "Only rooms showing this movie"
+// @Success      200  {file} file
+// @Failure      400  {string} string "format must be csv, ndjson or xlsx"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /export/rooms [get]
+func (ve *ViewExport) RoomsHandler(w http.ResponseWriter, r *http.Request) {
+	principal := auth.PrincipalFrom(r.Context())
+	ctx := controller_interfaces.WithMovie(r.Context(), r.URL.Query().Get("movieId"))
+	ve.stream(w, r, "rooms", roomColumns, func(tw tabular.Writer) error {
+		return ve.ControllerExport.Rooms(ctx, r.URL.Query().Get("cinemaId"), func(room *model_room.Room) error {
+			if !principal.AllowsRoom(room.Id) {
+				return nil
+			}
+			return tw.Row(room.Id, room.CinemaId, room.Number, room.Description)
+		})
+	})
+}
+
+// @Summary      Export room lineups
+// @Description  One line per movie scheduled in a room, scoped like the room listings.
+// @Tags         Export
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format   query string false "File format" Enums(csv, ndjson, xlsx) default(csv)
+// @Param        cinemaId query string false "Cinema ID"
+// @Param        roomId   query string false "Room ID"
+// @Success      200  {file} file
+// @Failure      400  {string} string "format must be csv, ndjson or xlsx"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /export/lineups [get]
+func (ve *ViewExport) LineupsHandler(w http.ResponseWriter, r *http.Request) {
+	principal := auth.PrincipalFrom(r.Context())
+	query := r.URL.Query()
+	ve.stream(w, r, "lineups", lineupColumns, func(tw tabular.Writer) error {
+		return ve.ControllerExport.Lineups(r.Context(), query.Get("cinemaId"), query.Get("roomId"), func(room *model_room.Room, m *model_movie.Movie) error {
+			if !principal.AllowsRoom(room.Id) {
+				return nil
+			}
+			return tw.Row(room.Id, room.CinemaId, room.Number, m.Id, m.Name, m.Director, year(m), m.DurationInSeconds, m.DurationInHours())
+		})
+	})
+}
+
+// stream writes the file as rows arrive; once bytes have reached the client an error can only cut the body short.
+func (ve *ViewExport) stream(w http.ResponseWriter, r *http.Request, name string, columns []string, rows func(tw tabular.Writer) error) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = tabular.FormatCSV
+	}
+	if format != tabular.FormatCSV && format != tabular.FormatNDJSON && format != tabular.FormatXLSX {
+		http.Error(w, "format must be csv, ndjson or xlsx", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", tabular.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+"."+format+`"`)
+	sr := &http_adapter.StatusRecorder{ResponseWriter: w}
+	tw, err := tabular.NewWriter(sr, format, columns)
+	if err == nil {
+		err = rows(tw)
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		return
+	}
+	ve.Logger.ErrorContext(r.Context(), "export failed", "export", name, "format", format, "err", err)
+	if sr.Status != 0 {
+		panic(http.ErrAbortHandler)
+	}
+	w.Header().Del("Content-Disposition")
+	status := http.StatusInternalServerError
+	if errors.Is(err, tenant.ErrOtherCinema) {
+		status = http.StatusForbidden
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// year leaves the cell empty for movies registered before the year was tracked.
+func year(m *model_movie.Movie) any {
+	if m.Year == 0 {
+		return nil
+	}
+	return m.Year
+}
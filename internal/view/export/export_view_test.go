@@ -0,0 +1,138 @@
+package view_export_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	view_export "github.com/rochaeduardo997/irede_golang_dev/internal/view/export"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeExport struct {
+	controller_interfaces.IExportController
+	rooms   []*model_room.Room
+	roomId  string
+	movieId string
+}
+
+func (fe *fakeExport) Movies(ctx context.Context, fn func(m *model_movie.Movie) error) error {
+	fe.roomId = controller_interfaces.RoomFrom(ctx)
+	fn(&model_movie.Movie{Id: "1", Name: "matrix", Director: "wachowski", Year: 1999, DurationInSeconds: 5760})
+	return fn(&model_movie.Movie{Id: "2", Name: "alien", Director: "scott", DurationInSeconds: 7020})
+}
+
+func (fe *fakeExport) Rooms(ctx context.Context, cinemaId string, fn func(r *model_room.Room) error) error {
+	if _, err := tenant.Resolve(ctx, cinemaId); err != nil {
+		return err
+	}
+	fe.movieId = controller_interfaces.MovieFrom(ctx)
+	for _, room := range fe.rooms {
+		if err := fn(room); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func forEachAdapter(t *testing.T, test func(t *testing.T, newAdapter http_adapter.Factory)) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) { test(t, newAdapter) })
+	}
+}
+
+func withPrincipal(p *auth.Principal) http_adapter.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+func get(handler http.Handler, url string) (*httptest.ResponseRecorder, string) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	body, _ := io.ReadAll(rec.Body)
+	return rec, string(body)
+}
+
+func TestExportMovies(t *testing.T) { forEachAdapter(t, testExportMovies) }
+
+func testExportMovies(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	view_export.NewViewExport(&view_export.ViewExport{HTTPAdapter: httpAdapter, ControllerExport: &fakeExport{}})
+
+	rec, body := get(handler, "/api/v1/export/movies")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="movies.csv"`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "id,name,director,year,durationInSeconds,durationInHours\n"+
+		"1,matrix,wachowski,1999,5760,01:36:00\n"+
+		"2,alien,scott,,7020,01:57:00\n", body)
+
+	rec, body = get(handler, "/api/v1/export/movies?format=ndjson")
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Contains(t, body, `"year":null`)
+
+	rec, _ = get(handler, "/api/v1/export/movies?format=pdf")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestExportRoomsScopedToPrincipal(t *testing.T) {
+	forEachAdapter(t, testExportRoomsScopedToPrincipal)
+}
+
+func testExportRoomsScopedToPrincipal(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	principal := &auth.Principal{Subject: "apikey:1", RoomIds: []string{"a"}, CinemaId: "cinema"}
+	rooms := []*model_room.Room{{Id: "a", CinemaId: "cinema", Number: 1}, {Id: "b", CinemaId: "cinema", Number: 2}}
+	view_export.NewViewExport(&view_export.ViewExport{
+		HTTPAdapter:      httpAdapter,
+		Middlewares:      []http_adapter.Middleware{withPrincipal(principal)},
+		ControllerExport: &fakeExport{rooms: rooms},
+	})
+
+	rec, body := get(handler, "/api/v1/export/rooms")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "id,cinemaId,number,description\na,cinema,1,\n", body)
+
+	rec, _ = get(handler, "/api/v1/export/rooms?cinemaId=other")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+}
+
+func TestExportWithListingFilters(t *testing.T) {
+	forEachAdapter(t, testExportWithListingFilters)
+}
+
+func testExportWithListingFilters(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	principal := &auth.Principal{Subject: "apikey:1", RoomIds: []string{"a"}}
+	export := &fakeExport{}
+	view_export.NewViewExport(&view_export.ViewExport{
+		HTTPAdapter:      httpAdapter,
+		Middlewares:      []http_adapter.Middleware{withPrincipal(principal)},
+		ControllerExport: export,
+	})
+
+	rec, _ := get(handler, "/api/v1/export/movies?roomId=a")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a", export.roomId)
+
+	export.roomId = ""
+	rec, _ = get(handler, "/api/v1/export/movies?roomId=b")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, export.roomId)
+
+	rec, _ = get(handler, "/api/v1/export/rooms?movieId=1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", export.movieId)
+}
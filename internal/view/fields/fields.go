@@ -0,0 +1,118 @@
+package view_fields
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Set is the fields= selection of a request, keyed by json name; a nil Set selects every field.
+type Set map[string]bool
+
+// always are kept whatever the selection, so trimmed representations stay addressable.
+var always = []string{"id", "_links"}
+
+// Parse reads the comma separated fields query parameter, checking each name against the fields of v, a pointer
+// to the representation.
+func Parse(r *http.Request, v any) (result Set, err error) {
+	value := r.URL.Query().Get("fields")
+	if value == "" {
+		return nil, nil
+	}
+	known := All(v)
+	result = Set{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q, fields must be among %s", name, strings.Join(names(v), ", "))
+		}
+		result[name] = true
+	}
+	for _, name := range always {
+		result[name] = known[name]
+	}
+	return
+}
+
+// All selects every field of v, a pointer to the representation.
+func All(v any) (result Set) {
+	result = Set{}
+	for _, name := range names(v) {
+		result[name] = true
+	}
+	return
+}
+
+func (s Set) Has(name string) bool {
+	return s == nil || s[name]
+}
+
+// Select trims v, a pointer to a representation, to the selected fields. Encoders only see the fields left, so the
+// result renders the same way in every codec.
+func (s Set) Select(v any) any {
+	if s == nil {
+		return v
+	}
+	value := reflect.ValueOf(v).Elem()
+	fields := []reflect.StructField{}
+	values := []reflect.Value{}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Name != "XMLName" && !s.Has(jsonName(field)) {
+			continue
+		}
+		fields = append(fields, field)
+		values = append(values, value.Field(i))
+	}
+	return build(fields, values)
+}
+
+// SelectPage is Select for every register of a listing, leaving the paging fields whole.
+func (s Set) SelectPage(v any) any {
+	if s == nil {
+		return v
+	}
+	value := reflect.ValueOf(v).Elem()
+	fields := []reflect.StructField{}
+	values := []reflect.Value{}
+	for i := 0; i < value.NumField(); i++ {
+		field, fieldValue := value.Type().Field(i), value.Field(i)
+		if field.Name == "Registers" {
+			registers := make([]any, fieldValue.Len())
+			for j := range registers {
+				registers[j] = s.Select(fieldValue.Index(j).Interface())
+			}
+			field.Type, fieldValue = reflect.TypeOf(registers), reflect.ValueOf(registers)
+		}
+		fields = append(fields, field)
+		values = append(values, fieldValue)
+	}
+	return build(fields, values)
+}
+
+func build(fields []reflect.StructField, values []reflect.Value) any {
+	result := reflect.New(reflect.StructOf(fields)).Elem()
+	for i, value := range values {
+		result.Field(i).Set(value)
+	}
+	return result.Addr().Interface()
+}
+
+func names(v any) (result []string) {
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "-" {
+			result = append(result, name)
+		}
+	}
+	return
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,68 @@
+package view_fields_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	view_fields "github.com/rochaeduardo997/irede_golang_dev/internal/view/fields"
+	"github.com/stretchr/testify/assert"
+)
+
+type register struct {
+	XMLName xml.Name `json:"-" xml:"register"`
+	Id      string   `json:"id" xml:"id"`
+	Name    string   `json:"name" xml:"name"`
+	Year    uint16   `json:"year" xml:"year"`
+}
+
+type page struct {
+	XMLName   xml.Name    `json:"-" xml:"page"`
+	Total     uint32      `json:"total" xml:"total"`
+	Registers []*register `json:"registers" xml:"registers>register"`
+}
+
+func parse(t *testing.T, query string) (view_fields.Set, error) {
+	t.Helper()
+	return view_fields.Parse(httptest.NewRequest(http.MethodGet, "/registers?"+query, nil), &register{})
+}
+
+func TestParse(t *testing.T) {
+	fields, err := parse(t, "")
+	assert.Nil(t, err)
+	assert.Nil(t, fields)
+	assert.True(t, fields.Has("name"))
+
+	fields, err = parse(t, "fields=name,%20year")
+	assert.Nil(t, err)
+	assert.Equal(t, view_fields.Set{"id": true, "name": true, "year": true, "_links": false}, fields)
+
+	_, err = parse(t, "fields=name,seats")
+	assert.EqualError(t, err, `unknown field "seats", fields must be among id, name, year`)
+}
+
+func TestSelect(t *testing.T) {
+	fields, _ := parse(t, "fields=year")
+	selected := fields.Select(&register{Id: "1", Name: "movie", Year: 2020})
+
+	body, _ := json.Marshal(selected)
+	assert.JSONEq(t, `{"id":"1","year":2020}`, string(body))
+	body, _ = xml.Marshal(selected)
+	assert.Equal(t, `<register><id>1</id><year>2020</year></register>`, string(body))
+}
+
+func TestSelectPage(t *testing.T) {
+	fields, _ := parse(t, "fields=name")
+	selected := fields.SelectPage(&page{Total: 1, Registers: []*register{{Id: "1", Name: "movie", Year: 2020}}})
+
+	body, _ := json.Marshal(selected)
+	assert.JSONEq(t, `{"total":1,"registers":[{"id":"1","name":"movie"}]}`, string(body))
+	body, _ = xml.Marshal(selected)
+	assert.Equal(t, `<page><total>1</total><registers><register><id>1</id><name>movie</name></register></registers></page>`, string(body))
+
+	var all view_fields.Set
+	original := &page{}
+	assert.Same(t, original, all.SelectPage(original))
+}
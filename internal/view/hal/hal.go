@@ -0,0 +1,85 @@
+package view_hal
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type Link struct {
+	Href string `json:"href" xml:"href,attr"`
+}
+
+// Links are the HAL _links of a representation; Write mirrors them in an RFC 8288 Link header.
+type Links struct {
+	Self   *Link `json:"self,omitempty" xml:"self,omitempty"`
+	First  *Link `json:"first,omitempty" xml:"first,omitempty"`
+	Prev   *Link `json:"prev,omitempty" xml:"prev,omitempty"`
+	Next   *Link `json:"next,omitempty" xml:"next,omitempty"`
+	Last   *Link `json:"last,omitempty" xml:"last,omitempty"`
+	Rooms  *Link `json:"rooms,omitempty" xml:"rooms,omitempty"`
+	Movies *Link `json:"movies,omitempty" xml:"movies,omitempty"`
+}
+
+func NewLink(format string, args ...any) *Link {
+	return &Link{Href: fmt.Sprintf(format, args...)}
+}
+
+// Pages links a listing page to the first, previous, next and last ones, href giving the URL of a page.
+func Pages(href func(page uint16) string, page, lastPage uint16) (result *Links) {
+	result = &Links{
+		Self:  &Link{Href: href(page)},
+		First: &Link{Href: href(1)},
+		Last:  &Link{Href: href(lastPage)},
+	}
+	if page > 1 {
+		result.Prev = &Link{Href: href(min(page-1, lastPage))}
+	}
+	if page < lastPage {
+		result.Next = &Link{Href: href(page + 1)}
+	}
+	return
+}
+
+// PathPages gives the links of listings taking the page in the path, format holding a %d for it. The request query,
+// filters included, is kept on every link.
+func PathPages(r *http.Request, format string) func(page uint16) string {
+	return func(page uint16) string {
+		return withQuery(fmt.Sprintf(format, page), r.URL.Query())
+	}
+}
+
+// QueryPages is PathPages for listings taking the page query parameter.
+func QueryPages(r *http.Request, path string) func(page uint16) string {
+	return func(page uint16) string {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(int(page)))
+		return withQuery(path, query)
+	}
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+func (l *Links) Header() string {
+	values := []string{}
+	for _, rel := range []struct {
+		name string
+		link *Link
+	}{{"self", l.Self}, {"first", l.First}, {"prev", l.Prev}, {"next", l.Next}, {"last", l.Last}, {"rooms", l.Rooms}, {"movies", l.Movies}} {
+		if rel.link != nil {
+			values = append(values, fmt.Sprintf(`<%s>; rel="%s"`, rel.link.Href, rel.name))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+func (l *Links) Write(w http.ResponseWriter) {
+	w.Header().Set("Link", l.Header())
+}
@@ -0,0 +1,45 @@
+package view_hal_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	view_hal "github.com/rochaeduardo997/irede_golang_dev/internal/view/hal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPages(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/rooms/all/2?movieId=abc", nil)
+	href := view_hal.PathPages(req, "/api/v1/rooms/all/%d")
+
+	links := view_hal.Pages(href, 2, 3)
+	assert.Equal(t, "/api/v1/rooms/all/2?movieId=abc", links.Self.Href)
+	assert.Equal(t, "/api/v1/rooms/all/1?movieId=abc", links.First.Href)
+	assert.Equal(t, "/api/v1/rooms/all/1?movieId=abc", links.Prev.Href)
+	assert.Equal(t, "/api/v1/rooms/all/3?movieId=abc", links.Next.Href)
+	assert.Equal(t, "/api/v1/rooms/all/3?movieId=abc", links.Last.Href)
+
+	links = view_hal.Pages(href, 1, 1)
+	assert.Nil(t, links.Prev)
+	assert.Nil(t, links.Next)
+	assert.Equal(t, links.First, links.Last)
+
+	links = view_hal.Pages(href, 7, 3)
+	assert.Equal(t, "/api/v1/rooms/all/3?movieId=abc", links.Prev.Href)
+	assert.Nil(t, links.Next)
+}
+
+func TestQueryPages(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/cinemas/c1/rooms?page=1&movieId=abc", nil)
+	links := view_hal.Pages(view_hal.QueryPages(req, "/api/v1/cinemas/c1/rooms"), 1, 2)
+	assert.Equal(t, "/api/v1/cinemas/c1/rooms?movieId=abc&page=1", links.Self.Href)
+	assert.Equal(t, "/api/v1/cinemas/c1/rooms?movieId=abc&page=2", links.Next.Href)
+}
+
+func TestHeader(t *testing.T) {
+	links := &view_hal.Links{Self: view_hal.NewLink("/api/v1/rooms/%s", "r1"), Movies: view_hal.NewLink("/api/v1/movies/all/1?roomId=%s", "r1")}
+	rec := httptest.NewRecorder()
+	links.Write(rec)
+	assert.Equal(t, `</api/v1/rooms/r1>; rel="self", </api/v1/movies/all/1?roomId=r1>; rel="movies"`, rec.Header().Get("Link"))
+}
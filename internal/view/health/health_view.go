@@ -0,0 +1,29 @@
+package view_health
+
+import (
+	"database/sql"
+	"net/http"
+
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+type HealthView struct {
+	HTTPAdapter http_adapter.IHTTP
+	Db          *sql.DB
+}
+
+func NewHealthView(hv *HealthView) (result *HealthView, err error) {
+	result = hv
+	result.HTTPAdapter.AddRoute("get", "/health", hv.HealthHandler)
+	return
+}
+
+func (hv *HealthView) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	err := hv.Db.PingContext(r.Context())
+	if err != nil {
+		http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
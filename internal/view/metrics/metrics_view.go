@@ -0,0 +1,17 @@
+package view_metrics
+
+import (
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/metrics"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+type MetricsView struct {
+	HTTPAdapter http_adapter.IHTTP
+	Metrics     *metrics.Metrics
+}
+
+func NewMetricsView(mv *MetricsView) (result *MetricsView, err error) {
+	result = mv
+	result.HTTPAdapter.AddRoute("get", "/metrics", mv.Metrics.Handler().ServeHTTP)
+	return
+}
@@ -0,0 +1,159 @@
+package view_movie
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	view_bulk "github.com/rochaeduardo997/irede_golang_dev/internal/view/bulk"
+)
+
+type BulkBody struct {
+	Id string `json:"id"`
+	Body
+}
+
+// @Summary      Create movies in bulk
+// @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode query string false "atomic (default) applies all or nothing, partial applies every valid item" Enums(atomic, partial)
+// @Param        data body []Body true "movies"
+// @Success      200  {object} view_bulk.Report
+// @Success      207  {object} view_bulk.Report "some items failed in partial mode"
+// @Failure      400  {string} string "invalid body or mode"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      422  {object} view_bulk.Report "atomic batch rolled back"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /movies/bulk [post]
+func (vm *ViewMovie) BulkCreateHandler(w http.ResponseWriter, r *http.Request) {
+	inputs := []*Body{}
+	mode, ok := view_bulk.Decode(w, r, &inputs, func() int { return len(inputs) })
+	if !ok {
+		return
+	}
+	movies := make([]*model_movie.Movie, len(inputs))
+	for i, input := range inputs {
+		if input != nil {
+			movies[i] = input.movie()
+		}
+	}
+	report := (&view_bulk.Batch{
+		Db:    vm.Db,
+		Mode:  mode,
+		Count: len(movies),
+		Validate: func(ctx context.Context, i int) error {
+			return validMovie(movies[i])
+		},
+		Apply: func(ctx context.Context, i int) (string, int, error) {
+			id, err := vm.ControllerMovie.Create(ctx, movies[i])
+			return id, http.StatusCreated, err
+		},
+	}).Run(r.Context())
+	vm.writeReport(w, r, report)
+}
+
+// @Summary      Update movies in bulk
+// @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode query string false "atomic (default) applies all or nothing, partial applies every valid item" Enums(atomic, partial)
+// @Param        data body []BulkBody true "movies with their id"
+// @Success      200  {object} view_bulk.Report
+// @Success      207  {object} view_bulk.Report "some items failed in partial mode"
+// @Failure      400  {string} string "invalid body or mode"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      422  {object} view_bulk.Report "atomic batch rolled back"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /movies/bulk [put]
+func (vm *ViewMovie) BulkUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	inputs := []*BulkBody{}
+	mode, ok := view_bulk.Decode(w, r, &inputs, func() int { return len(inputs) })
+	if !ok {
+		return
+	}
+	movies := make([]*model_movie.Movie, len(inputs))
+	for i, input := range inputs {
+		if input != nil {
+			movies[i] = input.movie()
+			movies[i].Id = input.Id
+		}
+	}
+	report := (&view_bulk.Batch{
+		Db:    vm.Db,
+		Mode:  mode,
+		Count: len(movies),
+		Validate: func(ctx context.Context, i int) error {
+			if movies[i] == nil || movies[i].Id == "" {
+				return errors.New("id must be provided")
+			}
+			return movies[i].IsValid()
+		},
+		Apply: func(ctx context.Context, i int) (string, int, error) {
+			_, err := vm.ControllerMovie.UpdateBy(ctx, movies[i].Id, movies[i])
+			return movies[i].Id, http.StatusOK, err
+		},
+	}).Run(r.Context())
+	vm.writeReport(w, r, report)
+}
+
+// @Summary      Delete movies in bulk
+// @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode query string false "atomic (default) applies all or nothing, partial applies every valid item" Enums(atomic, partial)
+// @Param        data body []string true "movie ids"
+// @Success      200  {object} view_bulk.Report
+// @Success      207  {object} view_bulk.Report "some items failed in partial mode"
+// @Failure      400  {string} string "invalid body or mode"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      422  {object} view_bulk.Report "atomic batch rolled back"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /movies/bulk [delete]
+func (vm *ViewMovie) BulkDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ids := []string{}
+	mode, ok := view_bulk.Decode(w, r, &ids, func() int { return len(ids) })
+	if !ok {
+		return
+	}
+	report := (&view_bulk.Batch{
+		Db:    vm.Db,
+		Mode:  mode,
+		Count: len(ids),
+		Validate: func(ctx context.Context, i int) error {
+			if ids[i] == "" {
+				return errors.New("id must be provided")
+			}
+			return nil
+		},
+		Apply: func(ctx context.Context, i int) (string, int, error) {
+			_, err := vm.ControllerMovie.DeleteBy(ctx, ids[i])
+			return ids[i], http.StatusOK, err
+		},
+	}).Run(r.Context())
+	vm.writeReport(w, r, report)
+}
+
+func validMovie(movie *model_movie.Movie) error {
+	if movie == nil {
+		return errors.New("movie must be provided")
+	}
+	return movie.IsValid()
+}
+
+func (vm *ViewMovie) writeReport(w http.ResponseWriter, r *http.Request, report *view_bulk.Report) {
+	err := view_bulk.WriteReport(w, report)
+	if err != nil {
+		vm.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+}
@@ -0,0 +1,63 @@
+package view_movie
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/importer"
+)
+
+// @Summary      Import movies from CSV or NDJSON
+// @Description  CSV needs a header with name, director and duration (seconds or HH:MM:SS); year is optional. NDJSON takes one movie object per line.
+// @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       text/csv
+// @Accept       application/x-ndjson
+// @Param        format query string false "csv or ndjson, defaults to the Content-Type" Enums(csv, ndjson)
+// @Param        dryRun query bool false "validate and report without saving"
+// @Param        upsert query bool false "update movies with the same name, director and year"
+// @Param        data body string true "file contents"
+// @Success      200  {object} importer.Report
+// @Success      207  {object} importer.Report "some rows failed"
+// @Failure      400  {string} string "unreadable file or unknown format"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /movies/import [post]
+func (vm *ViewMovie) ImportHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format, _, _ = mime.ParseMediaType(r.Header.Get("Content-Type"))
+	}
+	opts := &importer.Options{}
+	var err error
+	opts.Format, err = importer.ParseFormat(format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts.DryRun, _ = strconv.ParseBool(query.Get("dryRun"))
+	opts.Upsert, _ = strconv.ParseBool(query.Get("upsert"))
+	mi := &importer.MovieImporter{Db: vm.Db, Controller: vm.ControllerMovie}
+	report, err := mi.Import(r.Context(), r.Body, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resJSON, err := json.Marshal(report)
+	if err != nil {
+		vm.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(resJSON)
+}
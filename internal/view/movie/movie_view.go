@@ -2,65 +2,110 @@ package view_movie
 
 import (
 	"database/sql"
-	"encoding/json"
-	"log"
+	"encoding/xml"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 
-	"github.com/gorilla/mux"
 	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
 	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	view_fields "github.com/rochaeduardo997/irede_golang_dev/internal/view/fields"
+	view_hal "github.com/rochaeduardo997/irede_golang_dev/internal/view/hal"
 	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
 )
 
 type ViewMovie struct {
-	Db              *sql.DB
-	HTTPAdapter     http_adapter.IHTTP
-	ControllerMovie controller_interfaces.IGenericController[model_movie.Movie]
+	Db          *sql.DB
+	HTTPAdapter http_adapter.IHTTP
+	Logger      *slog.Logger
+	Middlewares []http_adapter.Middleware
+	// CreateMiddlewares run after the policy check on the create route only, e.g. idempotency keys.
+	CreateMiddlewares []http_adapter.Middleware
+	Policy            *auth.Policy
+	ControllerMovie   controller_interfaces.IGenericController[model_movie.Movie]
 }
 
 type Body struct {
-	Name              string `json:"name"`
-	Director          string `json:"director"`
-	DurationInSeconds int    `json:"durationInSeconds"`
+	XMLName           xml.Name `json:"-" xml:"movie"`
+	Name              string   `json:"name" xml:"name"`
+	Director          string   `json:"director" xml:"director"`
+	Year              uint16   `json:"year" xml:"year"`
+	DurationInSeconds uint16   `json:"durationInSeconds" xml:"durationInSeconds"`
+}
+
+// Response is how a movie goes over the wire, on its own and inside rooms.
+type Response struct {
+	XMLName           xml.Name        `json:"-" xml:"movie"`
+	Id                string          `json:"id" xml:"id"`
+	Name              string          `json:"name" xml:"name"`
+	Director          string          `json:"director" xml:"director"`
+	Year              uint16          `json:"year" xml:"year"`
+	DurationInSeconds uint16          `json:"durationInSeconds" xml:"durationInSeconds"`
+	DurationInHours   string          `json:"durationInHours" xml:"durationInHours" example:"02:16:00"`
+	Links             *view_hal.Links `json:"_links" xml:"_links"`
 }
 
 type FindAll struct {
-	Total     uint32
-	Page      uint16
-	Registers []*model_movie.Movie
+	XMLName   xml.Name        `json:"-" xml:"movies"`
+	Total     uint32          `json:"total" xml:"total"`
+	Page      uint16          `json:"page" xml:"page"`
+	Registers []*Response     `json:"registers" xml:"registers>movie"`
+	Links     *view_hal.Links `json:"_links" xml:"_links"`
 }
 
 func NewViewMovie(vm *ViewMovie) (result *ViewMovie) {
 	result = vm
+	if result.Logger == nil {
+		result.Logger = slog.Default()
+	}
 
-	result.HTTPAdapter.AddRoute("post", "/api/v1/movies", vm.CreateHandler)
-	result.HTTPAdapter.AddRoute("get", "/api/v1/movies/{id}", vm.FindByIdHandler)
-	result.HTTPAdapter.AddRoute("get", "/api/v1/movies/all/{page}", vm.FindAllHandler)
-	result.HTTPAdapter.AddRoute("put", "/api/v1/movies/{id}", vm.UpdateByIdHandler)
-	result.HTTPAdapter.AddRoute("delete", "/api/v1/movies/{id}", vm.DeleteByIdHandler)
+	group := result.HTTPAdapter.Group("/api/v1/movies", vm.Middlewares...)
+	group.AddRoute("post", "", vm.CreateHandler, append([]http_adapter.Middleware{vm.Policy.Require(auth.MoviesWrite)}, vm.CreateMiddlewares...)...)
+	group.AddRoute("post", "/import", vm.ImportHandler, vm.Policy.Require(auth.MoviesWrite))
+	group.AddRoute("post", "/bulk", vm.BulkCreateHandler, vm.Policy.Require(auth.MoviesWrite))
+	group.AddRoute("put", "/bulk", vm.BulkUpdateHandler, vm.Policy.Require(auth.MoviesWrite))
+	group.AddRoute("delete", "/bulk", vm.BulkDeleteHandler, vm.Policy.Require(auth.MoviesWrite))
+	group.AddRoute("get", "/{id}", vm.FindByIdHandler, vm.Policy.Require(auth.MoviesRead))
+	group.AddRoute("get", "/all/{page}", vm.FindAllHandler, vm.Policy.Require(auth.MoviesRead))
+	group.AddRoute("put", "/{id}", vm.UpdateByIdHandler, vm.Policy.Require(auth.MoviesWrite))
+	group.AddRoute("delete", "/{id}", vm.DeleteByIdHandler, vm.Policy.Require(auth.MoviesWrite))
 
 	return
 }
 
 // @Summary      Create a movie
 // @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json,xml,application/msgpack
 // @Param        data body Body true "body"
 // @Success      201  {string} string true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be JSON, XML or MessagePack"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Param        Idempotency-Key header string false "replays the first response for retries with the same key"
+// @Failure      409  {string} string "movie already exists"
+// @Failure      422  {string} string "idempotency key reused with a different request body"
 // @Router       /movies [post]
 func (vm *ViewMovie) CreateHandler(w http.ResponseWriter, r *http.Request) {
-	movie := &model_movie.Movie{}
-	err := json.NewDecoder(r.Body).Decode(&movie)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	input := &Body{}
+	if !http_adapter.Decode(w, r, input) {
 		return
 	}
-	err = movie.IsValid()
+	movie := input.movie()
+	err := movie.IsValid()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	result, err := vm.ControllerMovie.Create(movie)
+	result, err := vm.ControllerMovie.Create(r.Context(), movie)
+	if writeConflict(w, err) {
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -71,94 +116,127 @@ func (vm *ViewMovie) CreateHandler(w http.ResponseWriter, r *http.Request) {
 
 // @Summary      Get movie by id
 // @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json,xml,application/msgpack
 // @Param        id   path      string true  "Movie ID"
-// @Success      200  {object} model_movie.Movie
+// @Param        fields query   string false "comma separated attributes to return, e.g. name,year"
+// @Success      200  {object} Response
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      406  {string} string "Accept allows none of the representations"
+// @Failure      429  {string} string "rate limit exceeded"
 // @Router       /movies/{id} [get]
 func (vm *ViewMovie) FindByIdHandler(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["id"]
+	id := vm.HTTPAdapter.Param(r, "id")
 	if id == "" {
 		http.Error(w, "id must be provided", http.StatusBadRequest)
 		return
 	}
-	result, err := vm.ControllerMovie.FindBy(id)
+	codec, ok := http_adapter.Negotiate(w, r)
+	if !ok {
+		return
+	}
+	fields, err := view_fields.Parse(r, &Response{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := vm.ControllerMovie.FindBy(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	res := map[string]any{}
-	res["id"] = result.Id
-	res["name"] = result.Name
-	res["director"] = result.Director
-	res["durationInSeconds"] = result.DurationInSeconds
-	res["durationInHours"] = result.DurationInHours()
-	resJSON, err := json.Marshal(res)
+	res := NewResponse(result)
+	res.Links.Write(w)
+	err = codec.Write(w, http.StatusOK, fields.Select(res))
 	if err != nil {
-		log.Printf("Error happened in JSON marshal. Err: %s\n", err)
+		vm.Logger.ErrorContext(r.Context(), "response encoding failed", "err", err)
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(resJSON)
 }
 
 // @Summary      Get all movies
 // @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json,xml,application/msgpack
 // @Param        page   path      string true  "Page"
+// @Param        roomId query     string false "only movies shown in this room"
+// @Param        fields query     string false "comma separated attributes of each movie to return, e.g. name,year"
 // @Success      200  {object}    FindAll
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      406  {string} string "Accept allows none of the representations"
+// @Failure      429  {string} string "rate limit exceeded"
 // @Router       /movies/all/{page} [get]
 func (vm *ViewMovie) FindAllHandler(w http.ResponseWriter, r *http.Request) {
-	page := mux.Vars(r)["page"]
+	page := vm.HTTPAdapter.Param(r, "page")
 	pageInt, err := strconv.Atoi(page)
 	if err != nil {
 		http.Error(w, "page must be provided", http.StatusBadRequest)
 		return
 	}
-	result, err := vm.ControllerMovie.FindAll(uint16(pageInt))
+	roomId := r.URL.Query().Get("roomId")
+	if roomId != "" && !auth.PrincipalFrom(r.Context()).AllowsRoom(roomId) {
+		http.Error(w, "room not allowed for these credentials", http.StatusForbidden)
+		return
+	}
+	codec, ok := http_adapter.Negotiate(w, r)
+	if !ok {
+		return
+	}
+	fields, err := view_fields.Parse(r, &Response{})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	registers := []map[string]any{}
-	for _, movie := range result.Registers {
-		target := map[string]any{}
-		target["id"] = movie.Id
-		target["name"] = movie.Name
-		target["director"] = movie.Director
-		target["durationInSeconds"] = movie.DurationInSeconds
-		target["durationInHours"] = movie.DurationInHours()
-		registers = append(registers, target)
-	}
-	res := map[string]any{}
-	res["total"] = result.Total
-	res["page"] = result.Page
-	res["registers"] = registers
-	resJSON, err := json.Marshal(res)
+	ctx := controller_interfaces.WithRoom(r.Context(), roomId)
+	result, err := vm.ControllerMovie.FindAll(ctx, uint16(pageInt))
 	if err != nil {
-		log.Printf("Error happened in JSON marshal. Err: %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := &FindAll{Total: result.Total, Page: result.Page, Registers: NewResponses(result.Registers)}
+	res.Links = view_hal.Pages(view_hal.PathPages(r, "/api/v1/movies/all/%d"), result.Page, result.LastPage())
+	res.Links.Write(w)
+	err = codec.Write(w, http.StatusOK, fields.SelectPage(res))
+	if err != nil {
+		vm.Logger.ErrorContext(r.Context(), "response encoding failed", "err", err)
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(resJSON)
 }
 
 // @Summary      Update movie by id
 // @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      string true  "Movie ID"
+// @Accept       json,xml,application/msgpack
 // @Param        data body Body true "body"
 // @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be JSON, XML or MessagePack"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Failure      409  {string} string "movie already exists"
 // @Router       /movies/{id} [put]
 func (vm *ViewMovie) UpdateByIdHandler(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["id"]
-	movie := &model_movie.Movie{}
-	err := json.NewDecoder(r.Body).Decode(&movie)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	id := vm.HTTPAdapter.Param(r, "id")
+	input := &Body{}
+	if !http_adapter.Decode(w, r, input) {
 		return
 	}
+	movie := input.movie()
 	movie.Id = id
-	err = movie.IsValid()
+	err := movie.IsValid()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	result, err := vm.ControllerMovie.UpdateBy(id, movie)
+	result, err := vm.ControllerMovie.UpdateBy(r.Context(), id, movie)
+	if writeConflict(w, err) {
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -170,16 +248,21 @@ func (vm *ViewMovie) UpdateByIdHandler(w http.ResponseWriter, r *http.Request) {
 
 // @Summary      Delete a movie by id
 // @Tags         Movies
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      string true  "Movie ID"
 // @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
 // @Router       /movies/{id} [delete]
 func (vm *ViewMovie) DeleteByIdHandler(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["id"]
+	id := vm.HTTPAdapter.Param(r, "id")
 	if id == "" {
 		http.Error(w, "id must be provided", http.StatusBadRequest)
 		return
 	}
-	result, err := vm.ControllerMovie.DeleteBy(id)
+	result, err := vm.ControllerMovie.DeleteBy(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -188,3 +271,40 @@ func (vm *ViewMovie) DeleteByIdHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(res))
 }
+
+func writeConflict(w http.ResponseWriter, err error) bool {
+	var conflict *controller_interfaces.ConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	w.Header().Set("Location", "/api/v1/movies/"+conflict.ExistingId)
+	http.Error(w, conflict.Error(), http.StatusConflict)
+	return true
+}
+
+func (b *Body) movie() *model_movie.Movie {
+	return &model_movie.Movie{Name: b.Name, Director: b.Director, Year: b.Year, DurationInSeconds: b.DurationInSeconds}
+}
+
+func NewResponse(movie *model_movie.Movie) (result *Response) {
+	return &Response{
+		Id:                movie.Id,
+		Name:              movie.Name,
+		Director:          movie.Director,
+		Year:              movie.Year,
+		DurationInSeconds: movie.DurationInSeconds,
+		DurationInHours:   movie.DurationInHours(),
+		Links: &view_hal.Links{
+			Self:  view_hal.NewLink("/api/v1/movies/%s", movie.Id),
+			Rooms: view_hal.NewLink("/api/v1/rooms/all/1?movieId=%s", movie.Id),
+		},
+	}
+}
+
+func NewResponses(movies []*model_movie.Movie) (result []*Response) {
+	result = []*Response{}
+	for _, movie := range movies {
+		result = append(result, NewResponse(movie))
+	}
+	return
+}
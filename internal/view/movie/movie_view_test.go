@@ -2,45 +2,31 @@ package view_movie_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/joho/godotenv"
 	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
 	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
-	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/seed"
 	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
+	view_bulk "github.com/rochaeduardo997/irede_golang_dev/internal/view/bulk"
+	view_hal "github.com/rochaeduardo997/irede_golang_dev/internal/view/hal"
 	view_movie "github.com/rochaeduardo997/irede_golang_dev/internal/view/movie"
 	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
 	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
-func instanceMovie() (result *model_movie.Movie) {
-	expected := &model_movie.Movie{
-		Id:                "id",
-		Name:              "name",
-		Director:          "director",
-		DurationInSeconds: 3600,
-	}
-	result, _ = model_movie.NewMovie(expected)
-	return
-}
-
-func instanceDB() (result *sql.DB) {
-	err := godotenv.Load("../../../.env")
-	if err != nil {
-		log.Fatal("Error loading .env file, err: ", err)
-	}
-	result, _ = database.NewDatabaseConnection()
-	result.Query("DELETE FROM room_movies")
-	result.Query("DELETE FROM rooms")
-	result.Query("DELETE FROM movies")
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
 	return
 }
 
@@ -49,10 +35,24 @@ func instanceControllerMovie(db *sql.DB) (result controller_interfaces.IGenericC
 	return
 }
 
+func forEachAdapter(t *testing.T, test func(t *testing.T, newAdapter http_adapter.Factory)) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			test(t, newAdapter)
+		})
+	}
+}
+
 func TestInsert(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testInsert)
+}
+
+func testInsert(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
@@ -72,21 +72,60 @@ func TestInsert(t *testing.T) {
 	actual, err := io.ReadAll(resp.Body)
 	assert.Nil(t, err)
 
-	movie, err := cm.FindBy(string(actual))
+	movie, err := cm.FindBy(context.Background(), string(actual))
 	assert.Nil(t, err)
 	assert.Equal(t, movieBody["name"], movie.Name)
 	assert.Equal(t, movieBody["director"], movie.Director)
 	assert.Equal(t, movieBody["durationInSeconds"], int(movie.DurationInSeconds))
 }
 
+func TestFailInsertDuplicated(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFailInsertDuplicated)
+}
+
+func testFailInsertDuplicated(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	httpAdapter, handler := newAdapter()
+	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
+
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := cm.Create(context.Background(), movie)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	movieBody := map[string]any{}
+	movieBody["name"] = movie.Name
+	movieBody["director"] = movie.Director
+	movieBody["year"] = movie.Year
+	movieBody["durationInSeconds"] = movie.DurationInSeconds
+	bodyJSON, _ := json.Marshal(movieBody)
+	payload := bytes.NewBuffer(bodyJSON)
+	url := fmt.Sprintf("%s/api/v1/movies", server.URL)
+	resp, err := http.Post(url, "application/json", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "/api/v1/movies/"+id, resp.Header.Get("Location"))
+}
+
 func TestFindById(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFindById)
+}
+
+func testFindById(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
-	movie := instanceMovie()
-	id, _ := cm.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := cm.Create(context.Background(), movie)
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -101,16 +140,27 @@ func TestFindById(t *testing.T) {
 	bodyRes := &model_movie.Movie{}
 	json.Unmarshal(actual, &bodyRes)
 	assert.Equal(t, movie, bodyRes)
+	expected, _ := json.Marshal(map[string]any{
+		"id": id, "name": movie.Name, "director": movie.Director, "year": movie.Year, "durationInSeconds": movie.DurationInSeconds, "durationInHours": movie.DurationInHours(),
+		"_links": map[string]any{"self": map[string]any{"href": "/api/v1/movies/" + id}, "rooms": map[string]any{"href": "/api/v1/rooms/all/1?movieId=" + id}},
+	})
+	assert.JSONEq(t, string(expected), string(actual))
+	assert.Equal(t, fmt.Sprintf(`</api/v1/movies/%s>; rel="self", </api/v1/rooms/all/1?movieId=%s>; rel="rooms"`, id, id), resp.Header.Get("Link"))
 }
 
 func TestFindAll(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFindAll)
+}
+
+func testFindAll(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
-	movie := instanceMovie()
-	cm.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	cm.Create(context.Background(), movie)
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -129,14 +179,57 @@ func TestFindAll(t *testing.T) {
 	assert.Equal(t, 1, int(bodyRes.Page))
 }
 
+func TestFindAllLinks(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindAllLinks)
+}
+
+func testFindAllLinks(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	httpAdapter, handler := newAdapter()
+	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
+
+	g := seed.NewGenerator(seed.DefaultSeed)
+	for range controller_interfaces.PageSize + 1 {
+		cm.Create(context.Background(), g.Movie())
+	}
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/movies/all/%d", server.URL, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	bodyRes := &view_movie.FindAll{}
+	json.Unmarshal(actual, bodyRes)
+	assert.Len(t, bodyRes.Registers, 1)
+	assert.Equal(t, &view_hal.Links{
+		Self:  view_hal.NewLink("/api/v1/movies/all/2"),
+		First: view_hal.NewLink("/api/v1/movies/all/1"),
+		Prev:  view_hal.NewLink("/api/v1/movies/all/1"),
+		Last:  view_hal.NewLink("/api/v1/movies/all/2"),
+	}, bodyRes.Links)
+	assert.Equal(t, `</api/v1/movies/all/2>; rel="self", </api/v1/movies/all/1>; rel="first", </api/v1/movies/all/1>; rel="prev", </api/v1/movies/all/2>; rel="last"`, resp.Header.Get("Link"))
+}
+
 func TestUpdate(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testUpdate)
+}
+
+func testUpdate(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
-	movie := instanceMovie()
-	id, _ := cm.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := cm.Create(context.Background(), movie)
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -152,6 +245,7 @@ func TestUpdate(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	assert.Nil(t, err)
@@ -159,7 +253,7 @@ func TestUpdate(t *testing.T) {
 	_, err = io.ReadAll(resp.Body)
 	assert.Nil(t, err)
 
-	movie, err = cm.FindBy(string(id))
+	movie, err = cm.FindBy(context.Background(), string(id))
 	assert.Nil(t, err)
 	assert.Equal(t, "new_name", movie.Name)
 	assert.Equal(t, "new_director", movie.Director)
@@ -167,13 +261,18 @@ func TestUpdate(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testDelete)
+}
+
+func testDelete(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
-	movie := instanceMovie()
-	id, _ := cm.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	id, _ := cm.Create(context.Background(), movie)
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -190,15 +289,20 @@ func TestDelete(t *testing.T) {
 	_, err = io.ReadAll(resp.Body)
 	assert.Nil(t, err)
 
-	movies, err := cm.FindAll(1)
+	movies, err := cm.FindAll(context.Background(), 1)
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(movies.Registers))
 }
 
 func TestFailInsert(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFailInsert)
+}
+
+func testFailInsert(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
@@ -217,14 +321,120 @@ func TestFailInsert(t *testing.T) {
 	assert.Equal(t, "movie name must be provided\n", string(actual))
 }
 
+func TestFailInsertWithInvalidBody(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFailInsertWithInvalidBody)
+}
+
+func testFailInsertWithInvalidBody(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	httpAdapter, handler := newAdapter()
+	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/api/v1/movies", server.URL)
+	tests := []struct {
+		contentType string
+		body        string
+		status      int
+		message     string
+	}{
+		{"application/json", `{"id":"x","name":"name","director":"director","year":2000,"durationInSeconds":3600}`, http.StatusBadRequest, `unknown field "id"`},
+		{"application/json", `{"name":"name","director":"director","year":"2000","durationInSeconds":3600}`, http.StatusBadRequest, "year must be an integer from 0 to 65535"},
+		{"application/json", `{"name":"name","director":"director","year":2000,"durationInSeconds":3600} {}`, http.StatusBadRequest, "request body must contain a single JSON value"},
+		{"text/plain", `{"name":"name","director":"director","year":2000,"durationInSeconds":3600}`, http.StatusUnsupportedMediaType, "Content-Type must be one of application/json, application/xml, application/msgpack"},
+	}
+	for _, test := range tests {
+		resp, err := http.Post(url, test.contentType, bytes.NewBufferString(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Nil(t, err)
+		assert.Equal(t, test.status, resp.StatusCode)
+		assert.Equal(t, test.message+"\n", string(actual))
+	}
+	all, err := cm.FindAll(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Empty(t, all.Registers)
+}
+
+func TestRepresentations(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testRepresentations)
+}
+
+func testRepresentations(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	httpAdapter, handler := newAdapter()
+	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	body := `<movie><name>name</name><director>director</director><year>2001</year><durationInSeconds>3600</durationInSeconds></movie>`
+	resp, err := http.Post(fmt.Sprintf("%s/api/v1/movies", server.URL), "application/xml", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	get := func(accept string) (*http.Response, []byte) {
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/movies/%s", server.URL, id), nil)
+		req.Header.Set("Accept", accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		actual, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		return resp, actual
+	}
+	expected := &view_movie.Response{Id: string(id), Name: "name", Director: "director", Year: 2001, DurationInSeconds: 3600, DurationInHours: "01:00:00"}
+	expected.Links = &view_hal.Links{Self: view_hal.NewLink("/api/v1/movies/%s", id), Rooms: view_hal.NewLink("/api/v1/rooms/all/1?movieId=%s", id)}
+
+	resp, actual := get("application/xml")
+	assert.Equal(t, "application/xml", resp.Header.Get("Content-Type"))
+	bodyRes := &view_movie.Response{}
+	assert.Nil(t, xml.Unmarshal(actual, bodyRes))
+	bodyRes.XMLName = xml.Name{}
+	assert.Equal(t, expected, bodyRes)
+
+	resp, actual = get("application/msgpack")
+	assert.Equal(t, "application/msgpack", resp.Header.Get("Content-Type"))
+	decoded := map[string]any{}
+	assert.Nil(t, msgpack.Unmarshal(actual, &decoded))
+	assert.Equal(t, map[string]any{
+		"id": string(id), "name": "name", "director": "director", "year": uint16(2001), "durationInSeconds": uint16(3600), "durationInHours": "01:00:00",
+		"_links": map[string]any{"self": map[string]any{"href": "/api/v1/movies/" + string(id)}, "rooms": map[string]any{"href": "/api/v1/rooms/all/1?movieId=" + string(id)}},
+	}, decoded)
+
+	resp, _ = get("text/html")
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
+
 func TestFailFindByIdWithInvalidId(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFailFindByIdWithInvalidId)
+}
+
+func testFailFindByIdWithInvalidId(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
-	movie := instanceMovie()
-	cm.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	cm.Create(context.Background(), movie)
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -240,13 +450,18 @@ func TestFailFindByIdWithInvalidId(t *testing.T) {
 }
 
 func TestFailUpdateWithInvalidId(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFailUpdateWithInvalidId)
+}
+
+func testFailUpdateWithInvalidId(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
-	movie := instanceMovie()
-	cm.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	cm.Create(context.Background(), movie)
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -262,6 +477,7 @@ func TestFailUpdateWithInvalidId(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	assert.Nil(t, err)
@@ -272,13 +488,18 @@ func TestFailUpdateWithInvalidId(t *testing.T) {
 }
 
 func TestFailDeleteWithInvalidId(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFailDeleteWithInvalidId)
+}
+
+func testFailDeleteWithInvalidId(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
 
-	movie := instanceMovie()
-	cm.Create(movie)
+	movie := seed.NewGenerator(seed.DefaultSeed).Movie()
+	cm.Create(context.Background(), movie)
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -296,3 +517,107 @@ func TestFailDeleteWithInvalidId(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "movie not found\n", string(actual))
 }
+
+func TestBulkInsert(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testBulkInsert)
+}
+
+func testBulkInsert(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	httpAdapter, handler := newAdapter()
+	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	post := func(mode string, movies []map[string]any) (*http.Response, *view_bulk.Report) {
+		bodyJSON, _ := json.Marshal(movies)
+		url := fmt.Sprintf("%s/api/v1/movies/bulk?mode=%s", server.URL, mode)
+		resp, err := http.Post(url, "application/json", bytes.NewBuffer(bodyJSON))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		report := &view_bulk.Report{}
+		json.NewDecoder(resp.Body).Decode(report)
+		return resp, report
+	}
+	valid := map[string]any{"name": "name", "director": "director", "durationInSeconds": 3600}
+	other := map[string]any{"name": "other", "director": "director", "durationInSeconds": 3600}
+	invalid := map[string]any{"director": "director", "durationInSeconds": 3600}
+
+	resp, report := post("atomic", []map[string]any{valid, valid})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, http.StatusFailedDependency, report.Items[0].Status)
+	assert.Equal(t, http.StatusConflict, report.Items[1].Status)
+	total, _ := cm.FindAll(context.Background(), 1)
+	assert.Equal(t, uint32(0), total.Total)
+
+	resp, report = post("partial", []map[string]any{valid, invalid, other})
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	assert.Equal(t, 2, report.Succeeded)
+	assert.Equal(t, http.StatusBadRequest, report.Items[1].Status)
+	movie, err := cm.FindBy(context.Background(), report.Items[2].Id)
+	assert.Nil(t, err)
+	assert.Equal(t, "other", movie.Name)
+}
+
+func TestBulkRepeatedIds(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testBulkRepeatedIds)
+}
+
+func testBulkRepeatedIds(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	httpAdapter, handler := newAdapter()
+	view_movie.NewViewMovie(&view_movie.ViewMovie{Db: db, HTTPAdapter: httpAdapter, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	send := func(method, path string, body any) (*http.Response, *view_bulk.Report) {
+		bodyJSON, _ := json.Marshal(body)
+		req, err := http.NewRequest(method, server.URL+"/api/v1/movies/bulk"+path, bytes.NewBuffer(bodyJSON))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		report := &view_bulk.Report{}
+		json.NewDecoder(resp.Body).Decode(report)
+		return resp, report
+	}
+
+	resp, report := send(http.MethodPost, "", []map[string]any{{"name": "name", "director": "director", "durationInSeconds": 3600}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	id := report.Items[0].Id
+
+	resp, report = send(http.MethodPut, "", []map[string]any{
+		{"id": id, "name": "first", "director": "director", "durationInSeconds": 3600},
+		{"id": id, "name": "second", "director": "director", "durationInSeconds": 3600},
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, report.Succeeded)
+	movie, err := cm.FindBy(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, "second", movie.Name)
+
+	resp, report = send(http.MethodDelete, "", []string{id, id})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, http.StatusFailedDependency, report.Items[0].Status)
+	assert.Equal(t, "movie not found", report.Items[1].Error)
+	_, err = cm.FindBy(context.Background(), id)
+	assert.Nil(t, err)
+
+	resp, report = send(http.MethodDelete, "?mode=partial", []string{id, id})
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	assert.Equal(t, 1, report.Succeeded)
+	assert.Equal(t, "movie not found", report.Items[1].Error)
+}
@@ -0,0 +1,175 @@
+package view_room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	view_bulk "github.com/rochaeduardo997/irede_golang_dev/internal/view/bulk"
+)
+
+type BulkRoomReq struct {
+	Id string `json:"id"`
+	InputRoomReq
+}
+
+// @Summary      Create rooms in bulk
+// @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode query string false "atomic (default) applies all or nothing, partial applies every valid item" Enums(atomic, partial)
+// @Param        data body []InputRoomReq true "rooms"
+// @Success      200  {object} view_bulk.Report
+// @Success      207  {object} view_bulk.Report "some items failed in partial mode"
+// @Failure      400  {string} string "invalid body or mode"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      422  {object} view_bulk.Report "atomic batch rolled back"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /rooms/bulk [post]
+func (rm *ViewRoom) BulkCreateHandler(w http.ResponseWriter, r *http.Request) {
+	if principal := auth.PrincipalFrom(r.Context()); principal != nil && len(principal.RoomIds) > 0 {
+		http.Error(w, "credentials scoped to specific rooms cannot create rooms", http.StatusForbidden)
+		return
+	}
+	inputs := []*InputRoomReq{}
+	mode, ok := view_bulk.Decode(w, r, &inputs, func() int { return len(inputs) })
+	if !ok {
+		return
+	}
+	rooms := make([]*model_room.Room, len(inputs))
+	report := (&view_bulk.Batch{
+		Db:    rm.Db,
+		Mode:  mode,
+		Count: len(inputs),
+		Validate: func(ctx context.Context, i int) error {
+			if inputs[i] == nil {
+				return errors.New("room must be provided")
+			}
+			rooms[i] = rm.room(ctx, inputs[i])
+			rooms[i].CinemaId = inputs[i].CinemaId
+			return rooms[i].IsValid()
+		},
+		Apply: func(ctx context.Context, i int) (string, int, error) {
+			id, err := rm.ControllerRoom.Create(ctx, rooms[i])
+			return id, http.StatusCreated, err
+		},
+	}).Run(r.Context())
+	rm.writeReport(w, r, report)
+}
+
+// @Summary      Update rooms in bulk
+// @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode query string false "atomic (default) applies all or nothing, partial applies every valid item" Enums(atomic, partial)
+// @Param        data body []BulkRoomReq true "rooms with their id"
+// @Success      200  {object} view_bulk.Report
+// @Success      207  {object} view_bulk.Report "some items failed in partial mode"
+// @Failure      400  {string} string "invalid body or mode"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      422  {object} view_bulk.Report "atomic batch rolled back"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /rooms/bulk [put]
+func (rm *ViewRoom) BulkUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	inputs := []*BulkRoomReq{}
+	mode, ok := view_bulk.Decode(w, r, &inputs, func() int { return len(inputs) })
+	if !ok {
+		return
+	}
+	rooms := make([]*model_room.Room, len(inputs))
+	report := (&view_bulk.Batch{
+		Db:    rm.Db,
+		Mode:  mode,
+		Count: len(inputs),
+		Validate: func(ctx context.Context, i int) error {
+			if inputs[i] == nil || inputs[i].Id == "" {
+				return errors.New("id must be provided")
+			}
+			if err := checkRoom(ctx, inputs[i].Id); err != nil {
+				return err
+			}
+			rooms[i] = rm.room(ctx, &inputs[i].InputRoomReq)
+			return nil
+		},
+		Apply: func(ctx context.Context, i int) (string, int, error) {
+			_, err := rm.ControllerRoom.UpdateBy(ctx, inputs[i].Id, rooms[i])
+			return inputs[i].Id, http.StatusOK, err
+		},
+	}).Run(r.Context())
+	rm.writeReport(w, r, report)
+}
+
+// @Summary      Delete rooms in bulk
+// @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode query string false "atomic (default) applies all or nothing, partial applies every valid item" Enums(atomic, partial)
+// @Param        data body []string true "room ids"
+// @Success      200  {object} view_bulk.Report
+// @Success      207  {object} view_bulk.Report "some items failed in partial mode"
+// @Failure      400  {string} string "invalid body or mode"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      422  {object} view_bulk.Report "atomic batch rolled back"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /rooms/bulk [delete]
+func (rm *ViewRoom) BulkDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ids := []string{}
+	mode, ok := view_bulk.Decode(w, r, &ids, func() int { return len(ids) })
+	if !ok {
+		return
+	}
+	report := (&view_bulk.Batch{
+		Db:    rm.Db,
+		Mode:  mode,
+		Count: len(ids),
+		Validate: func(ctx context.Context, i int) error {
+			if ids[i] == "" {
+				return errors.New("id must be provided")
+			}
+			return checkRoom(ctx, ids[i])
+		},
+		Apply: func(ctx context.Context, i int) (string, int, error) {
+			_, err := rm.ControllerRoom.DeleteBy(ctx, ids[i])
+			return ids[i], http.StatusOK, err
+		},
+	}).Run(r.Context())
+	rm.writeReport(w, r, report)
+}
+
+// room resolves the input's movies the same way the single-room handlers do, skipping unknown ids.
+func (rm *ViewRoom) room(ctx context.Context, input *InputRoomReq) (result *model_room.Room) {
+	result = &model_room.Room{Number: input.Number, Description: input.Description}
+	for _, movieId := range input.MoviesId {
+		movie, err := rm.ControllerMovie.FindBy(ctx, movieId)
+		if err != nil {
+			continue
+		}
+		result.Movies = append(result.Movies, movie)
+	}
+	return
+}
+
+func checkRoom(ctx context.Context, id string) error {
+	if !auth.PrincipalFrom(ctx).AllowsRoom(id) {
+		return view_bulk.Fail(http.StatusForbidden, "room not allowed for these credentials")
+	}
+	return nil
+}
+
+func (rm *ViewRoom) writeReport(w http.ResponseWriter, r *http.Request, report *view_bulk.Report) {
+	err := view_bulk.WriteReport(w, report)
+	if err != nil {
+		rm.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+}
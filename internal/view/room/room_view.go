@@ -1,76 +1,133 @@
 package view_room
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
-	"log"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
-	"github.com/gorilla/mux"
 	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
 	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
 	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
+	view_fields "github.com/rochaeduardo997/irede_golang_dev/internal/view/fields"
+	view_hal "github.com/rochaeduardo997/irede_golang_dev/internal/view/hal"
+	view_movie "github.com/rochaeduardo997/irede_golang_dev/internal/view/movie"
 	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
 )
 
 type InputRoomReq struct {
-	Number      uint16
-	Description string
-	MoviesId    []string
+	XMLName     xml.Name `json:"-" xml:"room"`
+	CinemaId    string   `json:"cinemaId" xml:"cinemaId"`
+	Number      uint16   `json:"number" xml:"number"`
+	Description string   `json:"description" xml:"description"`
+	MoviesId    []string `json:"moviesId" xml:"moviesId>id"`
+}
+
+type Response struct {
+	XMLName     xml.Name               `json:"-" xml:"room"`
+	Id          string                 `json:"id" xml:"id"`
+	CinemaId    string                 `json:"cinemaId" xml:"cinemaId"`
+	Number      uint16                 `json:"number" xml:"number"`
+	Description string                 `json:"description" xml:"description"`
+	Movies      []*view_movie.Response `json:"movies" xml:"movies>movie"`
+	Links       *view_hal.Links        `json:"_links" xml:"_links"`
 }
 
 type FindAll struct {
-	Total     uint32
-	Page      uint16
-	Registers []*model_room.Room
+	XMLName   xml.Name        `json:"-" xml:"rooms"`
+	Total     uint32          `json:"total" xml:"total"`
+	Page      uint16          `json:"page" xml:"page"`
+	Registers []*Response     `json:"registers" xml:"registers>room"`
+	Links     *view_hal.Links `json:"_links" xml:"_links"`
 }
 
 type ViewRoom struct {
-	Db              *sql.DB
-	HTTPAdapter     http_adapter.IHTTP
-	ControllerRoom  controller_interfaces.IGenericController[model_room.Room]
-	ControllerMovie controller_interfaces.IGenericController[model_movie.Movie]
+	Db          *sql.DB
+	HTTPAdapter http_adapter.IHTTP
+	Logger      *slog.Logger
+	Middlewares []http_adapter.Middleware
+	// CreateMiddlewares run after the policy check on the create route only, e.g. idempotency keys.
+	CreateMiddlewares []http_adapter.Middleware
+	Policy            *auth.Policy
+	ControllerRoom    controller_interfaces.IGenericController[model_room.Room]
+	ControllerMovie   controller_interfaces.IGenericController[model_movie.Movie]
 }
 
 func NewViewRoom(rm *ViewRoom) (result *ViewRoom) {
 	result = rm
+	if result.Logger == nil {
+		result.Logger = slog.Default()
+	}
 
-	result.HTTPAdapter.AddRoute("post", "/api/v1/rooms", rm.CreateHandler)
-	result.HTTPAdapter.AddRoute("get", "/api/v1/rooms/{id}", rm.FindByIdHandler)
-	result.HTTPAdapter.AddRoute("get", "/api/v1/rooms/all/{page}", rm.FindAllHandler)
-	result.HTTPAdapter.AddRoute("put", "/api/v1/rooms/{id}", rm.UpdateByIdHandler)
-	result.HTTPAdapter.AddRoute("delete", "/api/v1/rooms/{id}", rm.DeleteByIdHandler)
+	group := result.HTTPAdapter.Group("/api/v1/rooms", rm.Middlewares...)
+	group.AddRoute("post", "", rm.CreateHandler, append([]http_adapter.Middleware{rm.Policy.Require(auth.RoomsWrite)}, rm.CreateMiddlewares...)...)
+	group.AddRoute("post", "/bulk", rm.BulkCreateHandler, rm.Policy.Require(auth.RoomsWrite))
+	group.AddRoute("put", "/bulk", rm.BulkUpdateHandler, rm.Policy.Require(auth.RoomsWrite))
+	group.AddRoute("delete", "/bulk", rm.BulkDeleteHandler, rm.Policy.Require(auth.RoomsWrite))
+	group.AddRoute("get", "/{id}", rm.FindByIdHandler, rm.Policy.Require(auth.RoomsRead))
+	group.AddRoute("get", "/all/{page}", rm.FindAllHandler, rm.Policy.Require(auth.RoomsRead))
+	group.AddRoute("put", "/{id}", rm.UpdateByIdHandler, rm.Policy.Require(auth.RoomsWrite))
+	group.AddRoute("delete", "/{id}", rm.DeleteByIdHandler, rm.Policy.Require(auth.RoomsWrite))
+
+	cinemas := result.HTTPAdapter.Group("/api/v1/cinemas", rm.Middlewares...)
+	cinemas.AddRoute("get", "/{id}/rooms", rm.FindAllByCinemaHandler, rm.Policy.Require(auth.RoomsRead))
 
 	return
 }
 
 // @Summary      Create a movie
 // @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json,xml,application/msgpack
 // @Param        data body InputRoomReq true "body"
 // @Success      201  {string} string true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be JSON, XML or MessagePack"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Param        Idempotency-Key header string false "replays the first response for retries with the same key"
+// @Failure      409  {string} string "room number already used in the cinema"
+// @Failure      422  {string} string "idempotency key reused with a different request body"
 // @Router       /rooms [post]
 func (rm *ViewRoom) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	if principal := auth.PrincipalFrom(r.Context()); principal != nil && len(principal.RoomIds) > 0 {
+		http.Error(w, "credentials scoped to specific rooms cannot create rooms", http.StatusForbidden)
+		return
+	}
 	input := &InputRoomReq{}
-	err := json.NewDecoder(r.Body).Decode(&input)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if !http_adapter.Decode(w, r, input) {
 		return
 	}
-	room := &model_room.Room{Number: input.Number, Description: input.Description}
+	room := &model_room.Room{CinemaId: input.CinemaId, Number: input.Number, Description: input.Description}
 	for _, movieId := range input.MoviesId {
-		movie, err := rm.ControllerMovie.FindBy(movieId)
+		movie, err := rm.ControllerMovie.FindBy(r.Context(), movieId)
 		if err != nil {
 			continue
 		}
 		room.Movies = append(room.Movies, movie)
 	}
-	err = room.IsValid()
+	err := room.IsValid()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	result, err := rm.ControllerRoom.Create(room)
+	result, err := rm.ControllerRoom.Create(r.Context(), room)
+	if writeConflict(w, err) {
+		return
+	}
+	if errors.Is(err, tenant.ErrOtherCinema) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -81,114 +138,173 @@ func (rm *ViewRoom) CreateHandler(w http.ResponseWriter, r *http.Request) {
 
 // @Summary      Get room by id
 // @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json,xml,application/msgpack
 // @Param        id   path      string true  "Room ID"
-// @Success      200  {object} model_room.Room
+// @Param        fields query   string false "comma separated attributes to return, e.g. number,movies; leaving movies out skips loading them"
+// @Success      200  {object} Response
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      406  {string} string "Accept allows none of the representations"
+// @Failure      429  {string} string "rate limit exceeded"
 // @Router       /rooms/{id} [get]
 func (rm *ViewRoom) FindByIdHandler(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["id"]
+	id := rm.HTTPAdapter.Param(r, "id")
 	if id == "" {
 		http.Error(w, "id must be provided", http.StatusBadRequest)
 		return
 	}
-	result, err := rm.ControllerRoom.FindBy(id)
+	if !allowsRoom(w, r, id) {
+		return
+	}
+	codec, ok := http_adapter.Negotiate(w, r)
+	if !ok {
+		return
+	}
+	fields, ok := selection(w, r, true)
+	if !ok {
+		return
+	}
+	result, err := rm.ControllerRoom.FindBy(withMovies(r.Context(), fields), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	res := map[string]any{}
-	res["id"] = result.Id
-	res["number"] = result.Number
-	res["description"] = result.Description
-	roomMovies := []any{}
-	for _, movie := range result.Movies {
-		target := map[string]any{}
-		target["id"] = movie.Id
-		target["name"] = movie.Name
-		target["director"] = movie.Director
-		target["durationInSeconds"] = movie.DurationInSeconds
-		target["durationInHours"] = movie.DurationInHours()
-		roomMovies = append(roomMovies, target)
-	}
-	res["movies"] = roomMovies
-	resJSON, err := json.Marshal(res)
+	res := roomResponse(result)
+	res.Links.Write(w)
+	err = codec.Write(w, http.StatusOK, fields.Select(res))
 	if err != nil {
-		log.Printf("Error happened in JSON marshal. Err: %s\n", err)
+		rm.Logger.ErrorContext(r.Context(), "response encoding failed", "err", err)
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(resJSON)
 }
 
 // @Summary      Get all rooms
 // @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json,xml,application/msgpack
 // @Param        page   path      string true  "Page"
+// @Param        movieId query    string false "only rooms showing this movie"
+// @Param        fields query     string false "comma separated attributes of each room to return, e.g. id,number"
+// @Param        expand query     string false "movies embeds the movies of each room, which are not loaded otherwise; fields=movies implies it" Enums(movies)
 // @Success      200  {object}    FindAll
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      406  {string} string "Accept allows none of the representations"
+// @Failure      429  {string} string "rate limit exceeded"
 // @Router       /rooms/all/{page} [get]
 func (rm *ViewRoom) FindAllHandler(w http.ResponseWriter, r *http.Request) {
-	page := mux.Vars(r)["page"]
+	page := rm.HTTPAdapter.Param(r, "page")
 	pageInt, err := strconv.Atoi(page)
 	if err != nil {
 		http.Error(w, "page must be provided", http.StatusBadRequest)
 		return
 	}
-	result, err := rm.ControllerRoom.FindAll(uint16(pageInt))
+	rm.writeRooms(w, r, uint16(pageInt), view_hal.PathPages(r, "/api/v1/rooms/all/%d"))
+}
+
+// @Summary      Get all rooms of a cinema
+// @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json,xml,application/msgpack
+// @Param        id     path      string true  "Cinema ID"
+// @Param        page   query     int    false "Page" default(1)
+// @Param        movieId query    string false "only rooms showing this movie"
+// @Param        fields query     string false "comma separated attributes of each room to return, e.g. id,number"
+// @Param        expand query     string false "movies embeds the movies of each room, which are not loaded otherwise; fields=movies implies it" Enums(movies)
+// @Success      200  {object}    FindAll
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      406  {string} string "Accept allows none of the representations"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /cinemas/{id}/rooms [get]
+func (rm *ViewRoom) FindAllByCinemaHandler(w http.ResponseWriter, r *http.Request) {
+	id := rm.HTTPAdapter.Param(r, "id")
+	ctx := tenant.WithCinema(r.Context(), id)
+	if cinemaId := tenant.CinemaFrom(r.Context()); cinemaId != "" && cinemaId != id {
+		http.Error(w, tenant.ErrOtherCinema.Error(), http.StatusForbidden)
+		return
+	}
+	page := 1
+	if value := r.URL.Query().Get("page"); value != "" {
+		var err error
+		page, err = strconv.Atoi(value)
+		if err != nil || page < 1 {
+			http.Error(w, "page must be a positive number", http.StatusBadRequest)
+			return
+		}
+	}
+	rm.writeRooms(w, r.WithContext(ctx), uint16(page), view_hal.QueryPages(r, "/api/v1/cinemas/"+id+"/rooms"))
+}
+
+func (rm *ViewRoom) writeRooms(w http.ResponseWriter, r *http.Request, page uint16, href func(page uint16) string) {
+	codec, ok := http_adapter.Negotiate(w, r)
+	if !ok {
+		return
+	}
+	fields, ok := selection(w, r, false)
+	if !ok {
+		return
+	}
+	ctx := controller_interfaces.WithMovie(withMovies(r.Context(), fields), r.URL.Query().Get("movieId"))
+	if principal := auth.PrincipalFrom(r.Context()); principal != nil {
+		ctx = controller_interfaces.WithRooms(ctx, principal.RoomIds)
+	}
+	result, err := rm.ControllerRoom.FindAll(ctx, page)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	registers := []map[string]any{}
+	res := &FindAll{Total: result.Total, Page: result.Page, Registers: []*Response{}}
 	for _, room := range result.Registers {
-		target := map[string]any{}
-		target["id"] = room.Id
-		target["number"] = room.Number
-		target["description"] = room.Description
-		roomMovies := []any{}
-		for _, movie := range room.Movies {
-			targetMovie := map[string]any{}
-			targetMovie["id"] = movie.Id
-			targetMovie["name"] = movie.Name
-			targetMovie["director"] = movie.Director
-			targetMovie["durationInSeconds"] = movie.DurationInSeconds
-			targetMovie["durationInHours"] = movie.DurationInHours()
-			roomMovies = append(roomMovies, targetMovie)
-		}
-		target["movies"] = roomMovies
-		registers = append(registers, target)
-	}
-	res := map[string]any{}
-	res["total"] = result.Total
-	res["page"] = result.Page
-	res["registers"] = registers
-	resJSON, err := json.Marshal(res)
+		res.Registers = append(res.Registers, roomResponse(room))
+	}
+	res.Links = view_hal.Pages(href, result.Page, result.LastPage())
+	res.Links.Write(w)
+	err = codec.Write(w, http.StatusOK, fields.SelectPage(res))
 	if err != nil {
-		log.Printf("Error happened in JSON marshal. Err: %s\n", err)
+		rm.Logger.ErrorContext(r.Context(), "response encoding failed", "err", err)
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(resJSON)
 }
 
 // @Summary      Update room by id
 // @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      string true  "Room ID"
+// @Accept       json,xml,application/msgpack
 // @Param        data body InputRoomReq true "body"
 // @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be JSON, XML or MessagePack"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Failure      409  {string} string "room number already used in the cinema"
 // @Router       /rooms/{id} [put]
 func (rm *ViewRoom) UpdateByIdHandler(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["id"]
+	id := rm.HTTPAdapter.Param(r, "id")
+	if !allowsRoom(w, r, id) {
+		return
+	}
 	input := &InputRoomReq{}
-	err := json.NewDecoder(r.Body).Decode(&input)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if !http_adapter.Decode(w, r, input) {
 		return
 	}
 	room := &model_room.Room{Number: input.Number, Description: input.Description}
 	for _, movieId := range input.MoviesId {
-		movie, err := rm.ControllerMovie.FindBy(movieId)
+		movie, err := rm.ControllerMovie.FindBy(r.Context(), movieId)
 		if err != nil {
 			continue
 		}
 		room.Movies = append(room.Movies, movie)
 	}
-	result, err := rm.ControllerRoom.UpdateBy(id, room)
+	result, err := rm.ControllerRoom.UpdateBy(r.Context(), id, room)
+	if writeConflict(w, err) {
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -200,16 +316,24 @@ func (rm *ViewRoom) UpdateByIdHandler(w http.ResponseWriter, r *http.Request) {
 
 // @Summary      Delete a room by id
 // @Tags         Rooms
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      string true  "Room ID"
 // @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
 // @Router       /rooms/{id} [delete]
 func (rm *ViewRoom) DeleteByIdHandler(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["id"]
+	id := rm.HTTPAdapter.Param(r, "id")
 	if id == "" {
 		http.Error(w, "id must be provided", http.StatusBadRequest)
 		return
 	}
-	result, err := rm.ControllerRoom.DeleteBy(id)
+	if !allowsRoom(w, r, id) {
+		return
+	}
+	result, err := rm.ControllerRoom.DeleteBy(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -218,3 +342,68 @@ func (rm *ViewRoom) DeleteByIdHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(res))
 }
+
+// selection reads fields= and expand=, answering 400 for unknown names. A single room embeds its movies unless
+// fields leaves them out, listings only when expand=movies asks for them.
+func selection(w http.ResponseWriter, r *http.Request, expanded bool) (result view_fields.Set, ok bool) {
+	result, err := view_fields.Parse(r, &Response{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	for _, name := range strings.Split(r.URL.Query().Get("expand"), ",") {
+		if name == "movies" {
+			expanded = true
+		} else if name != "" {
+			http.Error(w, fmt.Sprintf("unknown expansion %q, expand supports movies", name), http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	if !expanded && !result["movies"] {
+		if result == nil {
+			result = view_fields.All(&Response{})
+		}
+		delete(result, "movies")
+	}
+	return result, true
+}
+
+// withMovies skips loading the movies of the rooms when the selection leaves them out.
+func withMovies(ctx context.Context, fields view_fields.Set) context.Context {
+	if fields.Has("movies") {
+		return ctx
+	}
+	return controller_interfaces.WithoutMovies(ctx)
+}
+
+func allowsRoom(w http.ResponseWriter, r *http.Request, id string) bool {
+	if !auth.PrincipalFrom(r.Context()).AllowsRoom(id) {
+		http.Error(w, "room not allowed for these credentials", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func writeConflict(w http.ResponseWriter, err error) bool {
+	var conflict *controller_interfaces.ConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	w.Header().Set("Location", "/api/v1/rooms/"+conflict.ExistingId)
+	http.Error(w, conflict.Error(), http.StatusConflict)
+	return true
+}
+
+func roomResponse(room *model_room.Room) (result *Response) {
+	return &Response{
+		Id:          room.Id,
+		CinemaId:    room.CinemaId,
+		Number:      room.Number,
+		Description: room.Description,
+		Movies:      view_movie.NewResponses(room.Movies),
+		Links: &view_hal.Links{
+			Self:   view_hal.NewLink("/api/v1/rooms/%s", room.Id),
+			Movies: view_hal.NewLink("/api/v1/movies/all/1?roomId=%s", room.Id),
+		},
+	}
+}
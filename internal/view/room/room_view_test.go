@@ -2,20 +2,22 @@ package view_room_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/joho/godotenv"
 	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
 	controller_movie "github.com/rochaeduardo997/irede_golang_dev/internal/controller/movie"
 	controller_room "github.com/rochaeduardo997/irede_golang_dev/internal/controller/room"
-	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/seed"
 	model_movie "github.com/rochaeduardo997/irede_golang_dev/internal/model/movie"
 	model_room "github.com/rochaeduardo997/irede_golang_dev/internal/model/room"
 	view_room "github.com/rochaeduardo997/irede_golang_dev/internal/view/room"
@@ -23,35 +25,11 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func instanceMovie() (result *model_movie.Movie) {
-	expected := &model_movie.Movie{
-		Id:                "id",
-		Name:              "name",
-		Director:          "director",
-		DurationInSeconds: 3600,
-	}
-	result, _ = model_movie.NewMovie(expected)
-	return
-}
+const cinemaId = "cinema-id"
 
-func instanceRoom() (result *model_room.Room) {
-	result, _ = model_room.NewRoom(&model_room.Room{
-		Id:          "id",
-		Number:      200,
-		Description: "description",
-	})
-	return
-}
-
-func instanceDB() (result *sql.DB) {
-	err := godotenv.Load("../../../.env")
-	if err != nil {
-		log.Fatal("Error loading .env file, err: ", err)
-	}
-	result, _ = database.NewDatabaseConnection()
-	result.Query("DELETE FROM room_movies")
-	result.Query("DELETE FROM rooms")
-	result.Query("DELETE FROM movies")
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	result.Exec("INSERT INTO cinemas(id, name) VALUES(?, ?)", cinemaId, "cinema")
 	return
 }
 
@@ -65,20 +43,36 @@ func instanceControllerRoom(db *sql.DB, mc controller_interfaces.IGenericControl
 	return
 }
 
+func forEachAdapter(t *testing.T, test func(t *testing.T, newAdapter http_adapter.Factory)) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			test(t, newAdapter)
+		})
+	}
+}
+
 func TestInsert(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testInsert)
+}
+
+func testInsert(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
 
-	movie := instanceMovie()
-	movieId, _ := cm.Create(movie)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movieId, _ := cm.Create(context.Background(), movie)
 
 	movieBody := map[string]any{}
+	movieBody["cinemaId"] = cinemaId
 	movieBody["number"] = 300
 	movieBody["description"] = "description"
 	movieBody["moviesId"] = []string{movieId}
@@ -92,7 +86,7 @@ func TestInsert(t *testing.T) {
 	actual, err := io.ReadAll(resp.Body)
 	assert.Nil(t, err)
 
-	room, err := cr.FindBy(string(actual))
+	room, err := cr.FindBy(context.Background(), string(actual))
 	assert.Nil(t, err)
 	assert.Equal(t, movieBody["number"], int(room.Number))
 	assert.Equal(t, movieBody["description"], room.Description)
@@ -100,21 +94,27 @@ func TestInsert(t *testing.T) {
 }
 
 func TestFindById(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFindById)
+}
+
+func testFindById(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
 
-	movie := instanceMovie()
-	movieId, _ := cm.Create(movie)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movieId, _ := cm.Create(context.Background(), movie)
 	movie.Id = movieId
-	room := instanceRoom()
+	room := g.Room(cinemaId)
 	room.Movies = append(room.Movies, movie)
-	cr.Create(room)
+	cr.Create(context.Background(), room)
 
 	url := fmt.Sprintf("%s/api/v1/rooms/%s", server.URL, room.Id)
 	resp, err := http.Get(url)
@@ -126,26 +126,41 @@ func TestFindById(t *testing.T) {
 	bodyRes := &model_room.Room{}
 	json.Unmarshal(actual, &bodyRes)
 	assert.Equal(t, room, bodyRes)
+	expected, _ := json.Marshal(map[string]any{
+		"id": room.Id, "cinemaId": cinemaId, "number": room.Number, "description": room.Description,
+		"movies": []map[string]any{{
+			"id": movie.Id, "name": movie.Name, "director": movie.Director, "year": movie.Year, "durationInSeconds": movie.DurationInSeconds, "durationInHours": movie.DurationInHours(),
+			"_links": map[string]any{"self": map[string]any{"href": "/api/v1/movies/" + movie.Id}, "rooms": map[string]any{"href": "/api/v1/rooms/all/1?movieId=" + movie.Id}},
+		}},
+		"_links": map[string]any{"self": map[string]any{"href": "/api/v1/rooms/" + room.Id}, "movies": map[string]any{"href": "/api/v1/movies/all/1?roomId=" + room.Id}},
+	})
+	assert.JSONEq(t, string(expected), string(actual))
 }
 
 func TestFindAll(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFindAll)
+}
+
+func testFindAll(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
 
-	movie := instanceMovie()
-	movieId, _ := cm.Create(movie)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movieId, _ := cm.Create(context.Background(), movie)
 	movie.Id = movieId
-	room := instanceRoom()
+	room := g.Room(cinemaId)
 	room.Movies = append(room.Movies, movie)
-	cr.Create(room)
+	cr.Create(context.Background(), room)
 
-	url := fmt.Sprintf("%s/api/v1/rooms/all/%d", server.URL, 1)
+	url := fmt.Sprintf("%s/api/v1/rooms/all/%d?expand=movies", server.URL, 1)
 	resp, err := http.Get(url)
 	if err != nil {
 		t.Fatal(err)
@@ -159,29 +174,190 @@ func TestFindAll(t *testing.T) {
 	assert.Equal(t, 1, int(bodyRes.Page))
 }
 
+func TestFindAllAsXML(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindAllAsXML)
+}
+
+func testFindAllAsXML(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	cr := instanceControllerRoom(db, cm)
+	httpAdapter, handler := newAdapter()
+	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movie.Id, _ = cm.Create(context.Background(), movie)
+	room := g.Room(cinemaId, movie)
+	cr.Create(context.Background(), room)
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/rooms/all/%d?expand=movies", server.URL, 1), nil)
+	req.Header.Set("Accept", "application/xml, application/json;q=0.5")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "application/xml", resp.Header.Get("Content-Type"))
+	bodyRes := &view_room.FindAll{}
+	assert.Nil(t, xml.Unmarshal(actual, bodyRes))
+	assert.Equal(t, 1, int(bodyRes.Total))
+	assert.Equal(t, 1, int(bodyRes.Page))
+	assert.Equal(t, room.Id, bodyRes.Registers[0].Id)
+	assert.Equal(t, room.Number, bodyRes.Registers[0].Number)
+	assert.Equal(t, movie.Id, bodyRes.Registers[0].Movies[0].Id)
+	assert.Equal(t, movie.Year, bodyRes.Registers[0].Movies[0].Year)
+}
+
+func TestFindAllByMovie(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindAllByMovie)
+}
+
+func testFindAllByMovie(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	cr := instanceControllerRoom(db, cm)
+	httpAdapter, handler := newAdapter()
+	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movie.Id, _ = cm.Create(context.Background(), movie)
+	room := g.Room(cinemaId, movie)
+	cr.Create(context.Background(), room)
+	cr.Create(context.Background(), g.Room(cinemaId))
+
+	resp, err := http.Get(server.URL + "/api/v1/cinemas/" + cinemaId + "/rooms?movieId=" + movie.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	bodyRes := &view_room.FindAll{}
+	json.Unmarshal(actual, bodyRes)
+	assert.Equal(t, 1, int(bodyRes.Total))
+	assert.Equal(t, room.Id, bodyRes.Registers[0].Id)
+	assert.Equal(t, "/api/v1/cinemas/"+cinemaId+"/rooms?movieId="+movie.Id+"&page=1", bodyRes.Links.Self.Href)
+	assert.Equal(t, bodyRes.Links.Self, bodyRes.Links.Last)
+	assert.Nil(t, bodyRes.Links.Next)
+}
+
+func TestFindAllWithFields(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindAllWithFields)
+}
+
+func testFindAllWithFields(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	cr := instanceControllerRoom(db, cm)
+	httpAdapter, handler := newAdapter()
+	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movie.Id, _ = cm.Create(context.Background(), movie)
+	room := g.Room(cinemaId, movie)
+	cr.Create(context.Background(), room)
+
+	get := func(path string) (status int, body map[string]any) {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body = map[string]any{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+	register := func(body map[string]any) map[string]any {
+		return body["registers"].([]any)[0].(map[string]any)
+	}
+
+	status, body := get("/api/v1/rooms/all/1")
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, register(body), "movies")
+	assert.Equal(t, room.Description, register(body)["description"])
+
+	status, body = get("/api/v1/rooms/all/1?fields=number")
+	assert.Equal(t, http.StatusOK, status)
+	assert.ElementsMatch(t, []string{"id", "number", "_links"}, keys(register(body)))
+	assert.EqualValues(t, 1, body["total"])
+	assert.Contains(t, body, "_links")
+
+	status, body = get("/api/v1/rooms/all/1?fields=number,movies&expand=movies")
+	assert.Equal(t, http.StatusOK, status)
+	assert.ElementsMatch(t, []string{"id", "number", "movies", "_links"}, keys(register(body)))
+	assert.Len(t, register(body)["movies"], 1)
+
+	status, body = get("/api/v1/rooms/all/1?fields=movies")
+	assert.Equal(t, http.StatusOK, status)
+	assert.ElementsMatch(t, []string{"id", "movies", "_links"}, keys(register(body)))
+	assert.Len(t, register(body)["movies"], 1)
+
+	status, body = get("/api/v1/rooms/" + room.Id + "?fields=description")
+	assert.Equal(t, http.StatusOK, status)
+	assert.ElementsMatch(t, []string{"id", "description", "_links"}, keys(body))
+
+	for _, path := range []string{"/api/v1/rooms/all/1?fields=seats", "/api/v1/rooms/all/1?expand=cinema", "/api/v1/rooms/" + room.Id + "?fields=number,"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, path)
+	}
+}
+
+func keys(m map[string]any) (result []string) {
+	for key := range m {
+		result = append(result, key)
+	}
+	return
+}
+
 func TestUpdate(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testUpdate)
+}
+
+func testUpdate(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
 
-	movie := instanceMovie()
-	movieId, _ := cm.Create(movie)
-	movie2 := instanceMovie()
-	cm.Create(movie2)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movieId, _ := cm.Create(context.Background(), movie)
+	movie2 := g.Movie()
+	cm.Create(context.Background(), movie2)
 	movie.Id = movieId
-	room := instanceRoom()
+	room := g.Room(cinemaId)
 	room.Movies = append(room.Movies, movie)
-	cr.Create(room)
+	cr.Create(context.Background(), room)
 
 	movieBody := map[string]any{}
 	movieBody["number"] = 300
 	movieBody["description"] = "new_description"
-	movieBody["movie"] = "new_description"
 	movieBody["moviesId"] = []string{movie2.Id}
 	bodyJSON, _ := json.Marshal(movieBody)
 	payload := bytes.NewBuffer(bodyJSON)
@@ -190,6 +366,7 @@ func TestUpdate(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	assert.Nil(t, err)
@@ -197,7 +374,7 @@ func TestUpdate(t *testing.T) {
 	_, err = io.ReadAll(resp.Body)
 	assert.Nil(t, err)
 
-	room, err = cr.FindBy(room.Id)
+	room, err = cr.FindBy(context.Background(), room.Id)
 	assert.Nil(t, err)
 	assert.Equal(t, 300, int(room.Number))
 	assert.Equal(t, "new_description", room.Description)
@@ -205,21 +382,27 @@ func TestUpdate(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testDelete)
+}
+
+func testDelete(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
 	defer server.Close()
 
-	movie := instanceMovie()
-	movieId, _ := cm.Create(movie)
+	g := seed.NewGenerator(seed.DefaultSeed)
+	movie := g.Movie()
+	movieId, _ := cm.Create(context.Background(), movie)
 	movie.Id = movieId
-	room := instanceRoom()
+	room := g.Room(cinemaId)
 	room.Movies = append(room.Movies, movie)
-	cr.Create(room)
+	cr.Create(context.Background(), room)
 
 	url := fmt.Sprintf("%s/api/v1/rooms/%s", server.URL, room.Id)
 	req, err := http.NewRequest(http.MethodDelete, url, nil)
@@ -233,16 +416,21 @@ func TestDelete(t *testing.T) {
 	_, err = io.ReadAll(resp.Body)
 	assert.Nil(t, err)
 
-	rooms, err := cr.FindAll(1)
+	rooms, err := cr.FindAll(context.Background(), 1)
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(rooms.Registers))
 }
 
 func TestFailInsert(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFailInsert)
+}
+
+func testFailInsert(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
@@ -262,10 +450,15 @@ func TestFailInsert(t *testing.T) {
 }
 
 func TestFailFindByIdWithInvalidId(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFailFindByIdWithInvalidId)
+}
+
+func testFailFindByIdWithInvalidId(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
@@ -282,10 +475,15 @@ func TestFailFindByIdWithInvalidId(t *testing.T) {
 }
 
 func TestFailUpdateWithInvalidId(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFailUpdateWithInvalidId)
+}
+
+func testFailUpdateWithInvalidId(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
@@ -299,6 +497,7 @@ func TestFailUpdateWithInvalidId(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	assert.Nil(t, err)
@@ -309,10 +508,15 @@ func TestFailUpdateWithInvalidId(t *testing.T) {
 }
 
 func TestFailDeleteWithInvalidId(t *testing.T) {
-	db := instanceDB()
+	t.Parallel()
+	forEachAdapter(t, testFailDeleteWithInvalidId)
+}
+
+func testFailDeleteWithInvalidId(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
 	cm := instanceControllerMovie(db)
 	cr := instanceControllerRoom(db, cm)
-	httpAdapter, handler := http_adapter.NewGorillaMux()
+	httpAdapter, handler := newAdapter()
 	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
 
 	server := httptest.NewServer(handler)
@@ -331,3 +535,107 @@ func TestFailDeleteWithInvalidId(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "room not found\n", string(actual))
 }
+
+func TestFailFindByIdOutsideRoomScope(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFailFindByIdOutsideRoomScope)
+}
+
+func testFailFindByIdOutsideRoomScope(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	cr := instanceControllerRoom(db, cm)
+	id, _ := cr.Create(context.Background(), seed.NewGenerator(seed.DefaultSeed).Room(cinemaId))
+	scoped := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := &auth.Principal{Permissions: []string{auth.RoomsRead, auth.RoomsWrite}, RoomIds: []string{"other-room"}}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+	httpAdapter, handler := newAdapter()
+	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, Middlewares: []http_adapter.Middleware{scoped}, ControllerRoom: cr, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/rooms/%s", server.URL, id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = http.Post(fmt.Sprintf("%s/api/v1/rooms", server.URL), "application/json", bytes.NewBufferString(`{"Number":1,"Description":"d"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestFindAllByCinema(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindAllByCinema)
+}
+
+func testFindAllByCinema(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	db.Exec("INSERT INTO cinemas(id, name) VALUES(?, ?)", "other-cinema-id", "other cinema")
+	cm := instanceControllerMovie(db)
+	cr := instanceControllerRoom(db, cm)
+	id, _ := cr.Create(context.Background(), seed.NewGenerator(seed.DefaultSeed).Room(cinemaId))
+	cr.Create(context.Background(), &model_room.Room{CinemaId: "other-cinema-id", Number: 200, Description: "description"})
+	httpAdapter, handler := newAdapter()
+	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, ControllerRoom: cr, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/cinemas/%s/rooms", server.URL, cinemaId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	bodyRes := &controller_interfaces.FindAllResponse[model_room.Room]{}
+	json.Unmarshal(actual, &bodyRes)
+	assert.Equal(t, 1, int(bodyRes.Total))
+	assert.Equal(t, id, bodyRes.Registers[0].Id)
+	assert.Equal(t, cinemaId, bodyRes.Registers[0].CinemaId)
+}
+
+func TestFindAllWithinRoomScope(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindAllWithinRoomScope)
+}
+
+func testFindAllWithinRoomScope(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cm := instanceControllerMovie(db)
+	cr := instanceControllerRoom(db, cm)
+	var id string
+	for number := uint16(1); number <= controller_interfaces.PageSize+1; number++ {
+		id, _ = cr.Create(context.Background(), &model_room.Room{CinemaId: cinemaId, Number: number, Description: "description"})
+	}
+	scoped := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := &auth.Principal{Permissions: []string{auth.RoomsRead}, RoomIds: []string{id}}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+	httpAdapter, handler := newAdapter()
+	view_room.NewViewRoom(&view_room.ViewRoom{Db: db, HTTPAdapter: httpAdapter, Middlewares: []http_adapter.Middleware{scoped}, ControllerRoom: cr, ControllerMovie: cm})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/rooms/all/1", server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	bodyRes := &controller_interfaces.FindAllResponse[model_room.Room]{}
+	json.NewDecoder(resp.Body).Decode(bodyRes)
+	assert.Equal(t, uint32(1), bodyRes.Total)
+	assert.Len(t, bodyRes.Registers, 1)
+	assert.Equal(t, id, bodyRes.Registers[0].Id)
+	assert.NotContains(t, resp.Header.Get("Link"), `rel="next"`)
+}
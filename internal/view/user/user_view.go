@@ -0,0 +1,416 @@
+package view_user
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/tenant"
+	model_user "github.com/rochaeduardo997/irede_golang_dev/internal/model/user"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+)
+
+type ViewUser struct {
+	HTTPAdapter http_adapter.IHTTP
+	Logger      *slog.Logger
+	Middlewares []http_adapter.Middleware
+	// LoginMiddlewares wrap the unauthenticated login route, which Middlewares do not reach.
+	LoginMiddlewares []http_adapter.Middleware
+	Policy           *auth.Policy
+	Authenticator    *auth.Authenticator
+	ControllerUser   controller_interfaces.IUserController
+}
+
+type Body struct {
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Roles    []string `json:"roles"`
+	CinemaId string   `json:"cinemaId"`
+}
+
+type UpdateBody struct {
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+	CinemaId string   `json:"cinemaId"`
+}
+
+type PasswordBody struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+type LoginBody struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"tokenType"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type Response struct {
+	Id       string   `json:"id"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+	CinemaId string   `json:"cinemaId,omitempty"`
+	Disabled bool     `json:"disabled"`
+}
+
+type FindAll struct {
+	Total     uint32      `json:"total"`
+	Page      uint16      `json:"page"`
+	Registers []*Response `json:"registers"`
+}
+
+func NewViewUser(vu *ViewUser) (result *ViewUser) {
+	result = vu
+	if result.Logger == nil {
+		result.Logger = slog.Default()
+	}
+
+	group := result.HTTPAdapter.Group("/api/v1/users", vu.Middlewares...)
+	group.AddRoute("post", "", vu.CreateHandler, vu.Policy.Require(auth.UsersWrite))
+	group.AddRoute("get", "/{id}", vu.FindByIdHandler, vu.Policy.Require(auth.UsersRead))
+	group.AddRoute("get", "/all/{page}", vu.FindAllHandler, vu.Policy.Require(auth.UsersRead))
+	group.AddRoute("put", "/{id}", vu.UpdateByIdHandler, vu.Policy.Require(auth.UsersWrite))
+	group.AddRoute("delete", "/{id}", vu.DeleteByIdHandler, vu.Policy.Require(auth.UsersWrite))
+	group.AddRoute("put", "/{id}/password", vu.ChangePasswordHandler)
+	group.AddRoute("put", "/{id}/disable", vu.DisableHandler, vu.Policy.Require(auth.UsersWrite))
+	group.AddRoute("put", "/{id}/enable", vu.EnableHandler, vu.Policy.Require(auth.UsersWrite))
+
+	result.HTTPAdapter.AddRoute("post", "/api/v1/auth/login", vu.LoginHandler, vu.LoginMiddlewares...)
+
+	return
+}
+
+// @Summary      Create a user
+// @Tags         Users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        data body Body true "body"
+// @Success      201  {string} string true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission or role not grantable by the caller"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /users [post]
+func (vu *ViewUser) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	input := &Body{}
+	if !http_adapter.DecodeJSON(w, r, input) {
+		return
+	}
+	user, err := model_user.NewUser(&model_user.User{Username: input.Username, Password: input.Password, Roles: input.Roles, CinemaId: input.CinemaId})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	user.CinemaId, err = tenant.Resolve(r.Context(), user.CinemaId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if !vu.canGrant(w, r, user.Roles) {
+		return
+	}
+	result, err := vu.ControllerUser.Create(r.Context(), user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(result))
+}
+
+// @Summary      Get user by id
+// @Tags         Users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "User ID"
+// @Success      200  {object} Response
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /users/{id} [get]
+func (vu *ViewUser) FindByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vu.HTTPAdapter.Param(r, "id")
+	if id == "" {
+		http.Error(w, "id must be provided", http.StatusBadRequest)
+		return
+	}
+	result, err := vu.ControllerUser.FindBy(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resJSON, err := json.Marshal(userResponse(result))
+	if err != nil {
+		vu.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
+
+// @Summary      Get all users
+// @Tags         Users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        page   path      string true  "Page"
+// @Success      200  {object}    FindAll
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /users/all/{page} [get]
+func (vu *ViewUser) FindAllHandler(w http.ResponseWriter, r *http.Request) {
+	page := vu.HTTPAdapter.Param(r, "page")
+	pageInt, err := strconv.Atoi(page)
+	if err != nil {
+		http.Error(w, "page must be provided", http.StatusBadRequest)
+		return
+	}
+	result, err := vu.ControllerUser.FindAll(r.Context(), uint16(pageInt))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := &FindAll{Total: result.Total, Page: result.Page, Registers: []*Response{}}
+	for _, user := range result.Registers {
+		res.Registers = append(res.Registers, userResponse(user))
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		vu.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
+
+// @Summary      Update user by id
+// @Tags         Users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "User ID"
+// @Param        data body UpdateBody true "body"
+// @Success      200  {boolean} boolean true
+// @Failure      400  {string} string "user not found"
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission, role not grantable by the caller or cinema not allowed for these credentials"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /users/{id} [put]
+func (vu *ViewUser) UpdateByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vu.HTTPAdapter.Param(r, "id")
+	input := &UpdateBody{}
+	if !http_adapter.DecodeJSON(w, r, input) {
+		return
+	}
+	user := &model_user.User{Id: id, Username: input.Username, Roles: input.Roles, CinemaId: input.CinemaId}
+	err := user.IsValid()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	current, err := vu.ControllerUser.FindBy(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// An update keeps the user in its cinema unless the body names another, which only unscoped callers may do.
+	if user.CinemaId == "" {
+		user.CinemaId = current.CinemaId
+	}
+	if cinemaId := tenant.CinemaFrom(r.Context()); cinemaId != "" && user.CinemaId != cinemaId {
+		http.Error(w, tenant.ErrOtherCinema.Error(), http.StatusForbidden)
+		return
+	}
+	if !vu.canGrant(w, r, current.Roles) || !vu.canGrant(w, r, user.Roles) {
+		return
+	}
+	result, err := vu.ControllerUser.UpdateBy(r.Context(), id, user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+// @Summary      Delete a user by id
+// @Tags         Users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "User ID"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /users/{id} [delete]
+func (vu *ViewUser) DeleteByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id := vu.HTTPAdapter.Param(r, "id")
+	if id == "" {
+		http.Error(w, "id must be provided", http.StatusBadRequest)
+		return
+	}
+	result, err := vu.ControllerUser.DeleteBy(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+// @Summary      Change a user password
+// @Description  Users may change their own password by sending the current one; holders of users:write may reset any password.
+// @Tags         Users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "User ID"
+// @Param        data body PasswordBody true "body"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /users/{id}/password [put]
+func (vu *ViewUser) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	id := vu.HTTPAdapter.Param(r, "id")
+	input := &PasswordBody{}
+	if !http_adapter.DecodeJSON(w, r, input) {
+		return
+	}
+	principal := auth.PrincipalFrom(r.Context())
+	isSelf := principal != nil && principal.Subject == id
+	if !isSelf && !vu.Policy.Allows(principal, auth.UsersWrite) {
+		http.Error(w, "missing permission "+auth.UsersWrite, http.StatusForbidden)
+		return
+	}
+	if isSelf && !vu.Policy.Allows(principal, auth.UsersWrite) {
+		user, err := vu.ControllerUser.FindBy(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !user.CheckPassword(input.CurrentPassword) {
+			http.Error(w, "current password does not match", http.StatusForbidden)
+			return
+		}
+	}
+	result, err := vu.ControllerUser.ChangePassword(r.Context(), id, input.NewPassword)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+// @Summary      Disable a user account
+// @Tags         Users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "User ID"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /users/{id}/disable [put]
+func (vu *ViewUser) DisableHandler(w http.ResponseWriter, r *http.Request) {
+	vu.setDisabled(w, r, true)
+}
+
+// @Summary      Enable a user account
+// @Tags         Users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string true  "User ID"
+// @Success      200  {boolean} boolean true
+// @Failure      401  {string} string "missing or invalid bearer token"
+// @Failure      403  {string} string "missing permission"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /users/{id}/enable [put]
+func (vu *ViewUser) EnableHandler(w http.ResponseWriter, r *http.Request) {
+	vu.setDisabled(w, r, false)
+}
+
+func (vu *ViewUser) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	id := vu.HTTPAdapter.Param(r, "id")
+	result, err := vu.ControllerUser.SetDisabled(r.Context(), id, disabled)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := strconv.FormatBool(result)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(res))
+}
+
+// @Summary      Log in with username and password
+// @Tags         Auth
+// @Param        data body LoginBody true "body"
+// @Success      200  {object} LoginResponse
+// @Failure      401  {string} string "invalid credentials"
+// @Failure      403  {string} string "user disabled"
+// @Failure      413  {string} string "request body too large"
+// @Failure      415  {string} string "Content-Type must be application/json"
+// @Failure      429  {string} string "rate limit exceeded"
+// @Router       /auth/login [post]
+func (vu *ViewUser) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	input := &LoginBody{}
+	if !http_adapter.DecodeJSON(w, r, input) {
+		return
+	}
+	user, err := vu.ControllerUser.FindByUsername(r.Context(), input.Username)
+	if err != nil {
+		model_user.Unknown.CheckPassword(input.Password)
+	}
+	if err != nil || !user.CheckPassword(input.Password) {
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		return
+	}
+	if user.Disabled {
+		http.Error(w, "user disabled", http.StatusForbidden)
+		return
+	}
+	token, expiresAt, err := vu.Authenticator.Issue(&auth.Principal{Subject: user.Id, Roles: user.Roles, CinemaId: user.CinemaId})
+	if err != nil {
+		vu.Logger.ErrorContext(r.Context(), "token issue failed", "err", err)
+		http.Error(w, "token could not be issued", http.StatusInternalServerError)
+		return
+	}
+	resJSON, err := json.Marshal(&LoginResponse{Token: token, TokenType: "Bearer", ExpiresAt: expiresAt})
+	if err != nil {
+		vu.Logger.ErrorContext(r.Context(), "json marshal failed", "err", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resJSON)
+}
+
+// canGrant stops callers from handing out, or taking away, roles whose permissions they do not hold themselves.
+func (vu *ViewUser) canGrant(w http.ResponseWriter, r *http.Request, roles []string) bool {
+	principal := auth.PrincipalFrom(r.Context())
+	for _, role := range roles {
+		for _, permission := range vu.Policy.RolePermissions(role) {
+			if !vu.Policy.Allows(principal, permission) {
+				http.Error(w, "cannot grant role "+role, http.StatusForbidden)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func userResponse(user *model_user.User) (result *Response) {
+	return &Response{Id: user.Id, Username: user.Username, Roles: user.Roles, CinemaId: user.CinemaId, Disabled: user.Disabled}
+}
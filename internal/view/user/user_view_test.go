@@ -0,0 +1,341 @@
+package view_user_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controller_interfaces "github.com/rochaeduardo997/irede_golang_dev/internal/controller/interfaces"
+	controller_user "github.com/rochaeduardo997/irede_golang_dev/internal/controller/user"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/auth"
+	"github.com/rochaeduardo997/irede_golang_dev/internal/infra/database/dbtest"
+	model_user "github.com/rochaeduardo997/irede_golang_dev/internal/model/user"
+	view_user "github.com/rochaeduardo997/irede_golang_dev/internal/view/user"
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceUser() (result *model_user.User) {
+	result, _ = model_user.NewUser(&model_user.User{
+		Username: "username",
+		Password: "password",
+		Roles:    []string{"programmer"},
+	})
+	return
+}
+
+func instanceDB(t *testing.T) (result *sql.DB) {
+	result = dbtest.New(t)
+	return
+}
+
+func instanceControllerUser(db *sql.DB) (result controller_interfaces.IUserController) {
+	result, _ = controller_user.NewControllerUser(&controller_user.ControllerUser{Db: db})
+	return
+}
+
+func instanceAuthenticator() (result *auth.Authenticator) {
+	result, _ = auth.NewAuthenticator(&auth.Authenticator{HMACSecret: []byte("secret")})
+	return
+}
+
+func instanceView(cu controller_interfaces.IUserController, newAdapter http_adapter.Factory) http.Handler {
+	httpAdapter, handler := newAdapter()
+	view_user.NewViewUser(&view_user.ViewUser{HTTPAdapter: httpAdapter, Authenticator: instanceAuthenticator(), ControllerUser: cu})
+	return handler
+}
+
+func forEachAdapter(t *testing.T, test func(t *testing.T, newAdapter http_adapter.Factory)) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			test(t, newAdapter)
+		})
+	}
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testInsert)
+}
+
+func testInsert(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cu := instanceControllerUser(db)
+	server := httptest.NewServer(instanceView(cu, newAdapter))
+	defer server.Close()
+
+	userBody := map[string]any{}
+	userBody["username"] = "username"
+	userBody["password"] = "password"
+	userBody["roles"] = []string{"programmer"}
+	bodyJSON, _ := json.Marshal(userBody)
+	url := fmt.Sprintf("%s/api/v1/users", server.URL)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(bodyJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	user, err := cu.FindBy(context.Background(), string(actual))
+	assert.Nil(t, err)
+	assert.Equal(t, "username", user.Username)
+	assert.True(t, user.CheckPassword("password"))
+}
+
+func TestFindById(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFindById)
+}
+
+func testFindById(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cu := instanceControllerUser(db)
+	id, _ := cu.Create(context.Background(), instanceUser())
+	server := httptest.NewServer(instanceView(cu, newAdapter))
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/users/%s", server.URL, id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	bodyRes := &view_user.Response{}
+	json.Unmarshal(actual, &bodyRes)
+	assert.Equal(t, &view_user.Response{Id: id, Username: "username", Roles: []string{"programmer"}}, bodyRes)
+	assert.NotContains(t, string(actual), "password")
+}
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testLogin)
+}
+
+func testLogin(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cu := instanceControllerUser(db)
+	id, _ := cu.Create(context.Background(), instanceUser())
+	server := httptest.NewServer(instanceView(cu, newAdapter))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/api/v1/auth/login", server.URL)
+	bodyJSON, _ := json.Marshal(map[string]any{"username": "username", "password": "password"})
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(bodyJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	bodyRes := &view_user.LoginResponse{}
+	json.Unmarshal(actual, &bodyRes)
+	assert.Equal(t, "Bearer", bodyRes.TokenType)
+	principal, err := instanceAuthenticator().Parse(bodyRes.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, id, principal.Subject)
+
+	bodyJSON, _ = json.Marshal(map[string]any{"username": "username", "password": "wrong_password"})
+	resp, _ = http.Post(url, "application/json", bytes.NewBuffer(bodyJSON))
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	cu.SetDisabled(context.Background(), id, true)
+	bodyJSON, _ = json.Marshal(map[string]any{"username": "username", "password": "password"})
+	resp, _ = http.Post(url, "application/json", bytes.NewBuffer(bodyJSON))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestRateLimitBeforeAuthentication(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testRateLimitBeforeAuthentication)
+}
+
+func testRateLimitBeforeAuthentication(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cu := instanceControllerUser(db)
+	cu.Create(context.Background(), instanceUser())
+	authenticator := instanceAuthenticator()
+	clientLimit := http_adapter.RateLimit(http_adapter.RateLimitOptions{Rate: 0.001, Burst: 3})
+	httpAdapter, handler := newAdapter()
+	view_user.NewViewUser(&view_user.ViewUser{
+		HTTPAdapter:      httpAdapter,
+		Middlewares:      []http_adapter.Middleware{clientLimit, authenticator.Middleware()},
+		LoginMiddlewares: []http_adapter.Middleware{http_adapter.RateLimit(http_adapter.RateLimitOptions{Rate: 0.001, Burst: 3})},
+		Authenticator:    authenticator,
+		ControllerUser:   cu,
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	statuses := []int{}
+	for i := 0; i < 4; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/users/all/1", nil)
+		req.Header.Set("Authorization", "Bearer invalid")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, statuses)
+
+	statuses = []int{}
+	bodyJSON, _ := json.Marshal(map[string]any{"username": "username", "password": "wrong_password"})
+	for i := 0; i < 4; i++ {
+		resp, err := http.Post(server.URL+"/api/v1/auth/login", "application/json", bytes.NewBuffer(bodyJSON))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, statuses)
+}
+
+func TestUpdateScopedToCinema(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testUpdateScopedToCinema)
+}
+
+func testUpdateScopedToCinema(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	db.Exec("INSERT INTO cinemas(id, name) VALUES('cinema-a', 'a'), ('cinema-b', 'b')")
+	cu := instanceControllerUser(db)
+	own := instanceUser()
+	own.CinemaId = "cinema-a"
+	cu.Create(context.Background(), own)
+	other := instanceUser()
+	other.Username, other.CinemaId = "other", "cinema-b"
+	cu.Create(context.Background(), other)
+	scoped := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: "admin", CinemaId: "cinema-a"})))
+		})
+	}
+	httpAdapter, handler := newAdapter()
+	view_user.NewViewUser(&view_user.ViewUser{HTTPAdapter: httpAdapter, Middlewares: []http_adapter.Middleware{scoped}, Authenticator: instanceAuthenticator(), ControllerUser: cu})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	put := func(id string, body map[string]any) int {
+		bodyJSON, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/v1/users/"+id, bytes.NewBuffer(bodyJSON))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusBadRequest, put(other.Id, map[string]any{"username": "taken", "roles": []string{"admin"}}))
+	assert.Equal(t, http.StatusForbidden, put(own.Id, map[string]any{"username": "moved", "roles": []string{"admin"}, "cinemaId": "cinema-b"}))
+	assert.Equal(t, http.StatusOK, put(own.Id, map[string]any{"username": "renamed", "roles": []string{"admin"}}))
+
+	untouched, _ := cu.FindBy(context.Background(), other.Id)
+	assert.Equal(t, "other", untouched.Username)
+	assert.Equal(t, "cinema-b", untouched.CinemaId)
+	renamed, _ := cu.FindBy(context.Background(), own.Id)
+	assert.Equal(t, "renamed", renamed.Username)
+	assert.Equal(t, "cinema-a", renamed.CinemaId)
+}
+
+func TestDisable(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testDisable)
+}
+
+func testDisable(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cu := instanceControllerUser(db)
+	id, _ := cu.Create(context.Background(), instanceUser())
+	server := httptest.NewServer(instanceView(cu, newAdapter))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v1/users/%s/disable", server.URL, id), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	user, _ := cu.FindBy(context.Background(), id)
+	assert.Equal(t, true, user.Disabled)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testDelete)
+}
+
+func testDelete(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cu := instanceControllerUser(db)
+	id, _ := cu.Create(context.Background(), instanceUser())
+	server := httptest.NewServer(instanceView(cu, newAdapter))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/users/%s", server.URL, id), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "true", string(actual))
+}
+
+func TestFailGrantRoleNotHeld(t *testing.T) {
+	t.Parallel()
+	forEachAdapter(t, testFailGrantRoleNotHeld)
+}
+
+func testFailGrantRoleNotHeld(t *testing.T, newAdapter http_adapter.Factory) {
+	db := instanceDB(t)
+	cu := instanceControllerUser(db)
+	programmer := instanceUser()
+	cu.Create(context.Background(), programmer)
+	admin := instanceUser()
+	admin.Username, admin.Roles = "admin", []string{"admin"}
+	cu.Create(context.Background(), admin)
+	policy, _ := auth.LoadPolicy("")
+	manager := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := &auth.Principal{Subject: "manager", Roles: []string{"programmer"}, Permissions: []string{auth.UsersWrite}}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+	httpAdapter, handler := newAdapter()
+	view_user.NewViewUser(&view_user.ViewUser{HTTPAdapter: httpAdapter, Middlewares: []http_adapter.Middleware{manager}, Policy: policy, Authenticator: instanceAuthenticator(), ControllerUser: cu})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	send := func(method, path string, body map[string]any) int {
+		bodyJSON, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, server.URL+"/api/v1/users"+path, bytes.NewBuffer(bodyJSON))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "", map[string]any{"username": "root", "password": "password", "roles": []string{"admin"}}))
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "", map[string]any{"username": "box", "password": "password", "roles": []string{"box_office"}}))
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPut, "/"+programmer.Id, map[string]any{"username": "username", "roles": []string{"admin"}}))
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPut, "/"+admin.Id, map[string]any{"username": "admin", "roles": []string{"box_office"}}))
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/"+programmer.Id, map[string]any{"username": "username", "roles": []string{"box_office"}}))
+
+	_, err := cu.FindByUsername(context.Background(), "root")
+	assert.NotNil(t, err)
+	stored, _ := cu.FindBy(context.Background(), admin.Id)
+	assert.Equal(t, []string{"admin"}, stored.Roles)
+}
@@ -0,0 +1,55 @@
+package http_adapter
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type StatusRecorder struct {
+	http.ResponseWriter
+	Status int
+	Size   int
+}
+
+func (sr *StatusRecorder) WriteHeader(status int) {
+	if sr.Status == 0 {
+		sr.Status = status
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *StatusRecorder) Write(b []byte) (n int, err error) {
+	if sr.Status == 0 {
+		sr.Status = http.StatusOK
+	}
+	n, err = sr.ResponseWriter.Write(b)
+	sr.Size += n
+	return
+}
+
+func (sr *StatusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+func AccessLog(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			r = WithRouteCapture(r)
+			sr := &StatusRecorder{ResponseWriter: w}
+			next.ServeHTTP(sr, r)
+			if sr.Status == 0 {
+				sr.Status = http.StatusOK
+			}
+			logger.InfoContext(r.Context(), "http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"route", RoutePattern(r.Context()),
+				"status", sr.Status,
+				"bytes", sr.Size,
+				"duration_ms", time.Since(start).Milliseconds(),
+			)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package http_adapter
+
+import "net/http"
+
+type Factory func() (result IHTTP, handler http.Handler)
+
+var Adapters = map[string]Factory{
+	"gorilla":  NewGorillaMux,
+	"servemux": NewServeMux,
+}
@@ -0,0 +1,222 @@
+package http_adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec reads and writes one representation of the resources. Every codec follows the json struct tags, except XML,
+// which uses the xml ones.
+type Codec struct {
+	Name        string
+	ContentType string
+	mediaTypes  []string
+	suffix      string
+	marshal     func(v any) ([]byte, error)
+	decode      func(r io.Reader, v any) error
+	explain     func(err error) error
+}
+
+var errTrailingData = errors.New("request body must contain a single value")
+
+var JSON = &Codec{
+	Name:        "JSON",
+	ContentType: "application/json",
+	mediaTypes:  []string{"application/json"},
+	suffix:      "+json",
+	marshal:     json.Marshal,
+	decode: func(r io.Reader, v any) error {
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(v); err != nil {
+			return err
+		}
+		if decoder.Decode(&json.RawMessage{}) != io.EOF {
+			return errTrailingData
+		}
+		return nil
+	},
+	explain: explainJSON,
+}
+
+var XML = &Codec{
+	Name:        "XML",
+	ContentType: "application/xml",
+	mediaTypes:  []string{"application/xml", "text/xml"},
+	suffix:      "+xml",
+	marshal: func(v any) ([]byte, error) {
+		body, err := xml.Marshal(v)
+		return append([]byte(xml.Header), body...), err
+	},
+	// encoding/xml skips unknown elements, so XML bodies are only checked for syntax, types and trailing data.
+	decode: func(r io.Reader, v any) error {
+		decoder := xml.NewDecoder(r)
+		if err := decoder.Decode(v); err != nil {
+			return err
+		}
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				return nil
+			}
+			switch token := token.(type) {
+			case xml.Comment, xml.ProcInst:
+				continue
+			case xml.CharData:
+				if len(bytes.TrimSpace(token)) == 0 {
+					continue
+				}
+			}
+			return errTrailingData
+		}
+	},
+	explain: func(err error) error {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return errors.New("malformed XML: unexpected end of body")
+		}
+		return fmt.Errorf("malformed XML: %s", err)
+	},
+}
+
+var MessagePack = &Codec{
+	Name:        "MessagePack",
+	ContentType: "application/msgpack",
+	mediaTypes:  []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"},
+	marshal: func(v any) ([]byte, error) {
+		var buf bytes.Buffer
+		encoder := msgpack.NewEncoder(&buf)
+		encoder.SetCustomStructTag("json")
+		err := encoder.Encode(v)
+		return buf.Bytes(), err
+	},
+	decode: func(r io.Reader, v any) error {
+		decoder := msgpack.NewDecoder(r)
+		decoder.SetCustomStructTag("json")
+		decoder.DisallowUnknownFields(true)
+		if err := decoder.Decode(v); err != nil {
+			return err
+		}
+		if decoder.Skip() != io.EOF {
+			return errTrailingData
+		}
+		return nil
+	},
+	explain: func(err error) error {
+		message := strings.TrimPrefix(err.Error(), "msgpack: ")
+		switch {
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return errors.New("malformed MessagePack: unexpected end of body")
+		case strings.HasPrefix(message, "unknown field "):
+			return errors.New(message)
+		}
+		return fmt.Errorf("malformed MessagePack: %s", message)
+	},
+}
+
+// Codecs are the representations Negotiate and Decode choose from, the first being the default.
+var Codecs = []*Codec{JSON, XML, MessagePack}
+
+// Negotiate picks the codec for the response from the Accept header, defaulting to JSON, and answers 406 when the
+// header allows none of them.
+func Negotiate(w http.ResponseWriter, r *http.Request) (result *Codec, ok bool) {
+	w.Header().Add("Vary", "Accept")
+	result = negotiate(strings.Join(r.Header.Values("Accept"), ","))
+	if result == nil {
+		http.Error(w, "Accept must allow "+contentTypes(Codecs), http.StatusNotAcceptable)
+		return nil, false
+	}
+	return result, true
+}
+
+// Write answers status with v in the codec's representation.
+func (c *Codec) Write(w http.ResponseWriter, status int, v any) error {
+	body, err := c.marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", c.ContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
+
+func negotiate(accept string) (result *Codec) {
+	if strings.TrimSpace(accept) == "" {
+		return Codecs[0]
+	}
+	best := 0.0
+	for _, codec := range Codecs {
+		if q := codec.quality(accept); q > best {
+			result, best = codec, q
+		}
+	}
+	return
+}
+
+// quality is the weight the Accept header gives the codec through its most specific matching media range.
+func (c *Codec) quality(accept string) (result float64) {
+	specificity := 0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		s := c.specificity(mediaType)
+		if s <= specificity {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		result, specificity = q, s
+	}
+	return
+}
+
+func (c *Codec) specificity(mediaType string) int {
+	if c.accepts(mediaType) {
+		return 3
+	}
+	for _, own := range c.mediaTypes {
+		if major, _, _ := strings.Cut(own, "/"); mediaType == major+"/*" {
+			return 2
+		}
+	}
+	if mediaType == "*/*" {
+		return 1
+	}
+	return 0
+}
+
+func (c *Codec) accepts(mediaType string) bool {
+	for _, own := range c.mediaTypes {
+		if mediaType == own {
+			return true
+		}
+	}
+	return c.suffix != "" && strings.HasSuffix(mediaType, c.suffix)
+}
+
+func contentTypes(codecs []*Codec) string {
+	if len(codecs) == 1 {
+		return codecs[0].ContentType
+	}
+	names := []string{}
+	for _, codec := range codecs {
+		names = append(names, codec.ContentType)
+	}
+	return "one of " + strings.Join(names, ", ")
+}
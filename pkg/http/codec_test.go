@@ -0,0 +1,122 @@
+package http_adapter_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type codecBody struct {
+	XMLName xml.Name `json:"-" xml:"movie"`
+	Name    string   `json:"name" xml:"name"`
+	Year    uint16   `json:"year" xml:"year"`
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/xml", "application/xml"},
+		{"text/xml", "application/xml"},
+		{"text/*", "application/xml"},
+		{"application/x-msgpack", "application/msgpack"},
+		{"application/json;q=0.5, application/msgpack", "application/msgpack"},
+		{"application/xml;q=0.9, */*;q=0.1", "application/xml"},
+		{"application/json;q=0, */*", "application/xml"},
+		{"text/html, application/problem+json", "application/json"},
+		{"text/html", ""},
+		{"application/*;q=0", ""},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/movies", nil)
+		req.Header.Set("Accept", test.accept)
+		rec := httptest.NewRecorder()
+		codec, ok := http_adapter.Negotiate(rec, req)
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+		if test.contentType == "" {
+			assert.False(t, ok, test.accept)
+			assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+			continue
+		}
+		assert.True(t, ok, test.accept)
+		assert.Equal(t, test.contentType, codec.ContentType, test.accept)
+	}
+}
+
+func TestCodecWrite(t *testing.T) {
+	body := &codecBody{Name: "movie", Year: 2020}
+
+	rec := httptest.NewRecorder()
+	assert.Nil(t, http_adapter.XML.Write(rec, http.StatusOK, body))
+	assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+	assert.Equal(t, xml.Header+"<movie><name>movie</name><year>2020</year></movie>", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	assert.Nil(t, http_adapter.MessagePack.Write(rec, http.StatusCreated, body))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "application/msgpack", rec.Header().Get("Content-Type"))
+	decoded := map[string]any{}
+	assert.Nil(t, msgpack.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, "movie", decoded["name"])
+	assert.EqualValues(t, 2020, decoded["year"])
+}
+
+func TestDecode(t *testing.T) {
+	packed, _ := msgpack.Marshal(map[string]any{"name": "movie", "year": 2020})
+	bodies := map[string][]byte{
+		"application/json":    []byte(`{"name":"movie","year":2020}`),
+		"application/xml":     []byte(`<movie><name>movie</name><year>2020</year></movie>`),
+		"text/xml":            []byte(`<?xml version="1.0"?><movie><name>movie</name><year>2020</year></movie>` + "\n"),
+		"application/msgpack": packed,
+	}
+	for contentType, body := range bodies {
+		req := httptest.NewRequest(http.MethodPost, "/movies", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		input := &codecBody{}
+		assert.True(t, http_adapter.Decode(rec, req, input), contentType)
+		assert.Equal(t, "movie", input.Name, contentType)
+		assert.Equal(t, uint16(2020), input.Year, contentType)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	unknown, _ := msgpack.Marshal(map[string]any{"id": "1"})
+	packed, _ := msgpack.Marshal(map[string]any{"name": "movie"})
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		status      int
+		message     string
+	}{
+		{"unsupported content type", "text/plain", []byte(`{}`), http.StatusUnsupportedMediaType, "Content-Type must be one of application/json, application/xml, application/msgpack"},
+		{"xml type error", "application/xml", []byte(`<movie><year>abc</year></movie>`), http.StatusBadRequest, `malformed XML: strconv.ParseUint: parsing "abc": invalid syntax`},
+		{"xml trailing data", "application/xml", []byte(`<movie></movie><movie></movie>`), http.StatusBadRequest, "request body must contain a single XML value"},
+		{"xml empty body", "application/xml", nil, http.StatusBadRequest, "request body must be provided"},
+		{"msgpack unknown field", "application/msgpack", unknown, http.StatusBadRequest, `unknown field "id"`},
+		{"msgpack trailing data", "application/msgpack", append(packed, packed...), http.StatusBadRequest, "request body must contain a single MessagePack value"},
+		{"msgpack truncated body", "application/msgpack", packed[:len(packed)-2], http.StatusBadRequest, "malformed MessagePack: unexpected end of body"},
+		{"too large", "application/xml", []byte("<movie><name>" + strings.Repeat("a", http_adapter.MaxJSONBodyBytes) + "</name></movie>"), http.StatusRequestEntityTooLarge, "request body must be at most 1048576 bytes"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/movies", bytes.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			rec := httptest.NewRecorder()
+			assert.False(t, http_adapter.Decode(rec, req, &codecBody{}))
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.message+"\n", rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// MaxJSONBodyBytes caps decoded request bodies on top of the server-wide BodyLimit.
+const MaxJSONBodyBytes = 1 << 20
+
+// DecodeJSON strictly decodes the request body into target: the Content-Type must be JSON, unknown fields and
+// trailing data are rejected and the body is capped. It answers 415, 413 or 400 and returns false when the body is unusable.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, target any) bool {
+	return decode(w, r, target, JSON)
+}
+
+// Decode is DecodeJSON for any of Codecs, picked by the request Content-Type.
+func Decode(w http.ResponseWriter, r *http.Request, target any) bool {
+	return decode(w, r, target, Codecs...)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, target any, codecs ...*Codec) bool {
+	status, err := decodeBody(w, r, target, codecs)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return false
+	}
+	return true
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, target any, codecs []*Codec) (status int, err error) {
+	var codec *Codec
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil {
+		for _, candidate := range codecs {
+			if candidate.accepts(mediaType) {
+				codec = candidate
+				break
+			}
+		}
+	}
+	if codec == nil {
+		return http.StatusUnsupportedMediaType, errors.New("Content-Type must be " + contentTypes(codecs))
+	}
+	err = codec.decode(http.MaxBytesReader(w, r.Body, MaxJSONBodyBytes), target)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		return http.StatusOK, nil
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge, fmt.Errorf("request body must be at most %d bytes", tooLarge.Limit)
+	case errors.Is(err, io.EOF):
+		return http.StatusBadRequest, errors.New("request body must be provided")
+	case errors.Is(err, errTrailingData):
+		return http.StatusBadRequest, fmt.Errorf("request body must contain a single %s value", codec.Name)
+	}
+	return http.StatusBadRequest, codec.explain(err)
+}
+
+func explainJSON(err error) error {
+	var syntax *json.SyntaxError
+	var mismatch *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("malformed JSON: unexpected end of body")
+	case errors.As(err, &syntax):
+		return fmt.Errorf("malformed JSON at offset %d", syntax.Offset)
+	case errors.As(err, &mismatch):
+		field := mismatch.Field
+		if field == "" {
+			field = "request body"
+		}
+		return fmt.Errorf("%s must be %s", field, describe(mismatch.Type))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return err
+}
+
+func describe(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("an integer from 0 to %d", uint64(1)<<t.Bits()-1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return describe(t.Elem())
+	}
+	return "an object"
+}
@@ -0,0 +1,71 @@
+package http_adapter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type decodeBody struct {
+	Name string   `json:"name"`
+	Year uint16   `json:"year"`
+	Tags []string `json:"tags"`
+}
+
+func decode(contentType, body string) (*httptest.ResponseRecorder, *decodeBody, bool) {
+	req := httptest.NewRequest(http.MethodPost, "/movies", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	input := &decodeBody{}
+	ok := http_adapter.DecodeJSON(rec, req, input)
+	return rec, input, ok
+}
+
+func TestDecodeJSON(t *testing.T) {
+	rec, input, ok := decode("application/json; charset=utf-8", `{"name":"movie","year":2020,"tags":["a"]}`)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, &decodeBody{Name: "movie", Year: 2020, Tags: []string{"a"}}, input)
+
+	_, _, ok = decode("application/merge-patch+json", `{"name":"movie"}`)
+	assert.True(t, ok)
+}
+
+func TestDecodeJSONRejects(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		message     string
+	}{
+		{"missing content type", "", `{}`, http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+		{"wrong content type", "text/plain", `{}`, http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+		{"empty body", "application/json", ``, http.StatusBadRequest, "request body must be provided"},
+		{"truncated body", "application/json", `{"name":`, http.StatusBadRequest, "malformed JSON: unexpected end of body"},
+		{"syntax error", "application/json", `{"name" "movie"}`, http.StatusBadRequest, "malformed JSON at offset 9"},
+		{"unknown field", "application/json", `{"id":"1"}`, http.StatusBadRequest, `unknown field "id"`},
+		{"trailing data", "application/json", `{"name":"a"}{"name":"b"}`, http.StatusBadRequest, "request body must contain a single JSON value"},
+		{"string for integer", "application/json", `{"year":"2020"}`, http.StatusBadRequest, "year must be an integer from 0 to 65535"},
+		{"integer overflow", "application/json", `{"year":70000}`, http.StatusBadRequest, "year must be an integer from 0 to 65535"},
+		{"negative integer", "application/json", `{"year":-1}`, http.StatusBadRequest, "year must be an integer from 0 to 65535"},
+		{"number for string", "application/json", `{"name":1}`, http.StatusBadRequest, "name must be a string"},
+		{"object for array", "application/json", `{"tags":{}}`, http.StatusBadRequest, "tags must be an array"},
+		{"array for object", "application/json", `[]`, http.StatusBadRequest, "request body must be an object"},
+		{"too large", "application/json", `{"name":"` + strings.Repeat("a", http_adapter.MaxJSONBodyBytes) + `"}`, http.StatusRequestEntityTooLarge, "request body must be at most 1048576 bytes"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec, _, ok := decode(test.contentType, test.body)
+			assert.False(t, ok)
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.message+"\n", rec.Body.String())
+		})
+	}
+}
@@ -2,7 +2,7 @@ package http_adapter
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 
@@ -10,26 +10,46 @@ import (
 )
 
 type GorillaMux struct {
-	Router *mux.Router
+	Router      *mux.Router
+	middlewares []Middleware
+	handler     http.Handler
 }
 
-func NewGorillaMux() (result IHTTP, handler *mux.Router) {
+func NewGorillaMux() (result IHTTP, handler http.Handler) {
 	gm := &GorillaMux{}
 	gm.Router = mux.NewRouter()
+	gm.handler = gm.Router
 	result = gm
-	return result, gm.Router
+	return result, gm
 }
 
-func (gm *GorillaMux) AddRoute(method, url string, callback func(w http.ResponseWriter, r *http.Request)) {
-	gm.Router.HandleFunc(url, callback).Methods(method)
+func (gm *GorillaMux) AddRoute(method, url string, callback func(w http.ResponseWriter, r *http.Request), middlewares ...Middleware) {
+	gm.Router.Handle(url, routed(url, Chain(http.HandlerFunc(callback), middlewares...))).Methods(method)
 }
 
-func (gm *GorillaMux) Listen() {
-	PORT := os.Getenv("API_PORT")
-	log.Printf("server running on http://localhost:%s", PORT)
-	http.ListenAndServe(fmt.Sprintf(":%s", PORT), gm.Router)
+func (gm *GorillaMux) AddPrefixRoute(prefix string, handler http.Handler, middlewares ...Middleware) {
+	gm.Router.PathPrefix(prefix).Handler(routed(prefix+"*", Chain(handler, middlewares...)))
+}
+
+func (gm *GorillaMux) Group(prefix string, middlewares ...Middleware) IRouter {
+	return NewRouteGroup(gm, prefix, middlewares...)
+}
+
+func (gm *GorillaMux) Param(r *http.Request, name string) string {
+	return mux.Vars(r)[name]
 }
 
-func (gm *GorillaMux) Route() *mux.Router {
-	return gm.Router
+func (gm *GorillaMux) Use(middlewares ...Middleware) {
+	gm.middlewares = append(gm.middlewares, middlewares...)
+	gm.handler = Chain(gm.Router, gm.middlewares...)
+}
+
+func (gm *GorillaMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gm.handler.ServeHTTP(w, r)
+}
+
+func (gm *GorillaMux) Listen() {
+	PORT := os.Getenv("API_PORT")
+	slog.Info("server running", "url", fmt.Sprintf("http://localhost:%s", PORT))
+	http.ListenAndServe(fmt.Sprintf(":%s", PORT), gm)
 }
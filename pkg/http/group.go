@@ -0,0 +1,35 @@
+package http_adapter
+
+import "net/http"
+
+type RouteGroup struct {
+	Parent      IRouter
+	Prefix      string
+	Middlewares []Middleware
+}
+
+func NewRouteGroup(parent IRouter, prefix string, middlewares ...Middleware) (result IRouter) {
+	result = &RouteGroup{Parent: parent, Prefix: prefix, Middlewares: middlewares}
+	return
+}
+
+func (rg *RouteGroup) AddRoute(method, url string, callback func(w http.ResponseWriter, r *http.Request), middlewares ...Middleware) {
+	rg.Parent.AddRoute(method, rg.Prefix+url, callback, rg.with(middlewares)...)
+}
+
+func (rg *RouteGroup) AddPrefixRoute(prefix string, handler http.Handler, middlewares ...Middleware) {
+	rg.Parent.AddPrefixRoute(rg.Prefix+prefix, handler, rg.with(middlewares)...)
+}
+
+func (rg *RouteGroup) Group(prefix string, middlewares ...Middleware) IRouter {
+	return NewRouteGroup(rg, prefix, middlewares...)
+}
+
+func (rg *RouteGroup) Param(r *http.Request, name string) string {
+	return rg.Parent.Param(r, name)
+}
+
+func (rg *RouteGroup) with(middlewares []Middleware) (result []Middleware) {
+	result = append(result, rg.Middlewares...)
+	return append(result, middlewares...)
+}
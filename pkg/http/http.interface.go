@@ -2,12 +2,17 @@ package http_adapter
 
 import (
 	"net/http"
-
-	"github.com/gorilla/mux"
 )
 
+type IRouter interface {
+	AddRoute(method, url string, callback func(w http.ResponseWriter, r *http.Request), middlewares ...Middleware)
+	AddPrefixRoute(prefix string, handler http.Handler, middlewares ...Middleware)
+	Group(prefix string, middlewares ...Middleware) IRouter
+	Param(r *http.Request, name string) string
+}
+
 type IHTTP interface {
-	AddRoute(method, url string, callback func(w http.ResponseWriter, r *http.Request))
+	IRouter
+	Use(middlewares ...Middleware)
 	Listen()
-	Route() *mux.Router
 }
@@ -0,0 +1,189 @@
+package http_adapter
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+type IdempotencyOptions struct {
+	TTL time.Duration
+	// MaxEntries caps the stored keys; when full, the oldest completed response is evicted to make room.
+	MaxEntries int
+	// Key identifies the client so two callers can't replay each other's responses; empty falls back to the remote IP.
+	Key func(r *http.Request) string
+	Now func() time.Time
+}
+
+type idempotentResponse struct {
+	hash     [sha256.Size]byte
+	done     bool
+	status   int
+	header   http.Header
+	body     []byte
+	expireAt time.Time
+	element  *list.Element
+}
+
+type idempotencyStore struct {
+	opts      IdempotencyOptions
+	mu        sync.Mutex
+	responses map[string]*idempotentResponse
+	// completed holds the keys of done responses in completion order, which is also their expiry order.
+	completed *list.List
+}
+
+type responseCapture struct {
+	*StatusRecorder
+	body bytes.Buffer
+}
+
+func (rc *responseCapture) Write(b []byte) (n int, err error) {
+	n, err = rc.StatusRecorder.Write(b)
+	rc.body.Write(b[:n])
+	return
+}
+
+// Idempotency stores the first response given to an Idempotency-Key and replays it on retries until the TTL expires.
+// Reusing a key with a different body is rejected with 422; server errors are not stored so the client can retry.
+// Responses live in this process's memory, so replays are only guaranteed when retries reach the same instance.
+func Idempotency(opts IdempotencyOptions) Middleware {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 10000
+	}
+	store := &idempotencyStore{opts: opts, responses: map[string]*idempotentResponse{}, completed: list.New()}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			client := ""
+			if opts.Key != nil {
+				client = opts.Key(r)
+			}
+			if client == "" {
+				client = "ip:" + ClientIP(r)
+			}
+			storeKey := client + " " + r.Method + " " + r.URL.Path + " " + key
+			hash := sha256.Sum256(body)
+
+			stored, found, ok := store.reserve(storeKey, hash)
+			if !ok {
+				http.Error(w, "too many requests with an idempotency key in progress", http.StatusServiceUnavailable)
+				return
+			}
+			if found {
+				switch {
+				case stored.hash != hash:
+					http.Error(w, "idempotency key reused with a different request body", http.StatusUnprocessableEntity)
+				case !stored.done:
+					http.Error(w, "a request with this idempotency key is still in progress", http.StatusConflict)
+				default:
+					replay(w, stored)
+				}
+				return
+			}
+
+			rc := &responseCapture{StatusRecorder: &StatusRecorder{ResponseWriter: w}}
+			status := http.StatusInternalServerError
+			// a panicking handler leaves status as 500 so the key is released instead of stuck in progress
+			defer func() { store.complete(storeKey, status, w.Header(), rc.body.Bytes()) }()
+			next.ServeHTTP(rc, r)
+			status = rc.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+		})
+	}
+}
+
+// reserve returns the stored response for key, or marks key as in progress when it is unknown. It fails when the
+// store is full of requests still in progress, since evicting one of them would let its retry run twice.
+func (s *idempotencyStore) reserve(key string, hash [sha256.Size]byte) (result *idempotentResponse, found, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.opts.Now()
+	s.sweep(now)
+	result, found = s.responses[key]
+	if found {
+		return result, true, true
+	}
+	if len(s.responses) >= s.opts.MaxEntries {
+		oldest := s.completed.Front()
+		if oldest == nil {
+			return nil, false, false
+		}
+		s.remove(oldest.Value.(string))
+	}
+	s.responses[key] = &idempotentResponse{hash: hash}
+	return nil, false, true
+}
+
+func (s *idempotencyStore) complete(key string, status int, header http.Header, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status >= http.StatusInternalServerError {
+		s.remove(key)
+		return
+	}
+	stored := s.responses[key]
+	stored.done = true
+	stored.status = status
+	stored.header = header.Clone()
+	stored.body = bytes.Clone(body)
+	stored.expireAt = s.opts.Now().Add(s.opts.TTL)
+	stored.element = s.completed.PushBack(key)
+}
+
+// sweep drops expired responses, which all sit at the front of completed.
+func (s *idempotencyStore) sweep(now time.Time) {
+	for oldest := s.completed.Front(); oldest != nil; oldest = s.completed.Front() {
+		key := oldest.Value.(string)
+		if !now.After(s.responses[key].expireAt) {
+			return
+		}
+		s.remove(key)
+	}
+}
+
+func (s *idempotencyStore) remove(key string) {
+	if stored, found := s.responses[key]; found && stored.element != nil {
+		s.completed.Remove(stored.element)
+	}
+	delete(s.responses, key)
+}
+
+func replay(w http.ResponseWriter, stored *idempotentResponse) {
+	for name, values := range stored.header {
+		if _, set := w.Header()[name]; !set {
+			w.Header()[name] = values
+		}
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(stored.status)
+	w.Write(stored.body)
+}
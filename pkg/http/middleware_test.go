@@ -0,0 +1,315 @@
+package http_adapter_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func tagMiddleware(tag string) http_adapter.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Order", tag)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) { forEachAdapter(t, testMiddlewareOrder) }
+
+func testMiddlewareOrder(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.Use(tagMiddleware("global1"), tagMiddleware("global2"))
+	httpAdapter.AddRoute("get", "/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}, tagMiddleware("route1"), tagMiddleware("route2"))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"global1", "global2", "route1", "route2"}, rec.Header().Values("X-Order"))
+}
+
+func TestGlobalMiddlewareRunsOnUnmatchedRoutes(t *testing.T) {
+	forEachAdapter(t, testGlobalMiddlewareRunsOnUnmatchedRoutes)
+}
+
+func testGlobalMiddlewareRunsOnUnmatchedRoutes(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.Use(tagMiddleware("global"))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "global", rec.Header().Get("X-Order"))
+}
+
+func TestRecovery(t *testing.T) { forEachAdapter(t, testRecovery) }
+
+func testRecovery(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.Use(http_adapter.Recovery(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	httpAdapter.AddRoute("get", "/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "internal server error\n", rec.Body.String())
+}
+
+func TestRequestID(t *testing.T) { forEachAdapter(t, testRequestID) }
+
+func testRequestID(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.Use(http_adapter.RequestID())
+	httpAdapter.AddRoute("get", "/id", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(http_adapter.RequestIDFrom(r.Context())))
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/id", nil))
+	generated := rec.Header().Get(http_adapter.RequestIDHeader)
+	assert.Greater(t, len(generated), 10)
+	assert.Equal(t, generated, rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/id", nil)
+	req.Header.Set(http_adapter.RequestIDHeader, "incoming-id")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "incoming-id", rec.Header().Get(http_adapter.RequestIDHeader))
+	assert.Equal(t, "incoming-id", rec.Body.String())
+}
+
+func TestCORSPreflight(t *testing.T) { forEachAdapter(t, testCORSPreflight) }
+
+func testCORSPreflight(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.Use(http_adapter.CORS(http_adapter.CORSOptions{AllowedOrigins: []string{"http://allowed.com"}, MaxAge: 600}))
+	httpAdapter.AddRoute("post", "/movies", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/movies", nil)
+	req.Header.Set("Origin", "http://allowed.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "http://allowed.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), http.MethodPost)
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+
+	req = httptest.NewRequest(http.MethodOptions, "/movies", nil)
+	req.Header.Set("Origin", "http://denied.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestBodyLimit(t *testing.T) { forEachAdapter(t, testBodyLimit) }
+
+func testBodyLimit(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.Use(http_adapter.BodyLimit(4))
+	httpAdapter.AddRoute("post", "/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.Write(body)
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("abc")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "abc", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("abcdef")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestRateLimit(t *testing.T) { forEachAdapter(t, testRateLimit) }
+
+func testRateLimit(t *testing.T, newAdapter http_adapter.Factory) {
+	now := time.Unix(0, 0)
+	httpAdapter, handler := newAdapter()
+	limit := http_adapter.RateLimit(http_adapter.RateLimitOptions{
+		Rate:  1,
+		Burst: 2,
+		Key:   func(r *http.Request) string { return r.Header.Get("X-Client") },
+		Now:   func() time.Time { return now },
+	})
+	httpAdapter.AddRoute("get", "/ping", func(w http.ResponseWriter, r *http.Request) {}, limit)
+
+	request := func(client string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("X-Client", client)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("a")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Reset"))
+
+	rec = request("a")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+	rec = request("a")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, request("b").Code)
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, request("a").Code)
+}
+
+func TestRateLimitFallsBackToClientIP(t *testing.T) {
+	httpAdapter, handler := http_adapter.NewServeMux()
+	limit := http_adapter.RateLimit(http_adapter.RateLimitOptions{Rate: 1, Burst: 1})
+	httpAdapter.AddRoute("get", "/ping", func(w http.ResponseWriter, r *http.Request) {}, limit)
+
+	request := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1000"))
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:2000"))
+	assert.Equal(t, http.StatusOK, request("10.0.0.2:1000"))
+}
+
+func TestIdempotency(t *testing.T) { forEachAdapter(t, testIdempotency) }
+
+func testIdempotency(t *testing.T, newAdapter http_adapter.Factory) {
+	now := time.Unix(0, 0)
+	calls := 0
+	httpAdapter, handler := newAdapter()
+	idempotency := http_adapter.Idempotency(http_adapter.IdempotencyOptions{
+		TTL: time.Hour,
+		Key: func(r *http.Request) string { return r.Header.Get("X-Client") },
+		Now: func() time.Time { return now },
+	})
+	httpAdapter.AddRoute("post", "/movies", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Location", "/movies/"+string(body))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(strings.Repeat("x", calls)))
+	}, idempotency)
+
+	request := func(client, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/movies", strings.NewReader(body))
+		req.Header.Set("X-Client", client)
+		req.Header.Set(http_adapter.IdempotencyKeyHeader, key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("a", "k1", "1")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "x", rec.Body.String())
+	assert.Empty(t, rec.Header().Get(http_adapter.IdempotentReplayedHeader))
+
+	rec = request("a", "k1", "1")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "x", rec.Body.String())
+	assert.Equal(t, "/movies/1", rec.Header().Get("Location"))
+	assert.Equal(t, "true", rec.Header().Get(http_adapter.IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	rec = request("a", "k1", "2")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	assert.Equal(t, "xx", request("b", "k1", "1").Body.String())
+	assert.Equal(t, "xxx", request("a", "", "1").Body.String())
+
+	now = now.Add(2 * time.Hour)
+	assert.Equal(t, "xxxx", request("a", "k1", "2").Body.String())
+}
+
+func TestIdempotencyEvictsOldestWhenFull(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	httpAdapter, handler := http_adapter.NewServeMux()
+	httpAdapter.AddRoute("post", "/movies", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Query().Has("slow") {
+			<-release
+		}
+		w.WriteHeader(http.StatusCreated)
+	}, http_adapter.Idempotency(http_adapter.IdempotencyOptions{MaxEntries: 2}))
+
+	request := func(key, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/movies"+query, strings.NewReader("{}"))
+		req.Header.Set(http_adapter.IdempotencyKeyHeader, key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	request("k1", "")
+	request("k2", "")
+	request("k3", "")
+	assert.Equal(t, "true", request("k3", "").Header().Get(http_adapter.IdempotentReplayedHeader))
+	assert.Empty(t, request("k1", "").Header().Get(http_adapter.IdempotentReplayedHeader))
+	assert.Equal(t, int32(4), calls.Load())
+
+	done := make(chan struct{})
+	for _, key := range []string{"k4", "k5"} {
+		go func() {
+			request(key, "?slow")
+			done <- struct{}{}
+		}()
+	}
+	assert.Eventually(t, func() bool { return request("k6", "").Code == http.StatusServiceUnavailable }, time.Second, time.Millisecond)
+	close(release)
+	<-done
+	<-done
+}
+
+func TestIdempotencyDoesNotStoreServerErrors(t *testing.T) {
+	calls := 0
+	httpAdapter, handler := http_adapter.NewServeMux()
+	httpAdapter.AddRoute("post", "/movies", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}, http_adapter.Idempotency(http_adapter.IdempotencyOptions{}))
+
+	request := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/movies", strings.NewReader("{}"))
+		req.Header.Set(http_adapter.IdempotencyKeyHeader, "k1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusInternalServerError, request())
+	assert.Equal(t, http.StatusCreated, request())
+	assert.Equal(t, http.StatusCreated, request())
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,116 @@
+package http_adapter
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type RateLimitOptions struct {
+	// Rate is how many requests per second are refilled into each client's bucket.
+	Rate  float64
+	Burst int
+	// Key identifies the client; requests with an empty key fall back to the remote IP.
+	Key func(r *http.Request) string
+	Now func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	opts      RateLimitOptions
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// RateLimit applies a token bucket per client; each call keeps its own buckets so route groups can have different limits.
+func RateLimit(opts RateLimitOptions) Middleware {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if opts.Burst < 1 {
+		opts.Burst = 1
+	}
+	limiter := &rateLimiter{opts: opts, buckets: map[string]*bucket{}, lastSweep: opts.Now()}
+	return func(next http.Handler) http.Handler {
+		if opts.Rate <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := ""
+			if opts.Key != nil {
+				key = opts.Key(r)
+			}
+			if key == "" {
+				key = "ip:" + ClientIP(r)
+			}
+			remaining, reset, retryAfter, ok := limiter.take(key)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(opts.Burst))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(reset))
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (l *rateLimiter) take(key string) (remaining, reset, retryAfter int, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.opts.Now()
+	burst := float64(l.opts.Burst)
+	l.sweep(now)
+	b, found := l.buckets[key]
+	if !found {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*l.opts.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		ok = true
+	} else {
+		retryAfter = seconds((1 - b.tokens) / l.opts.Rate)
+	}
+	remaining = int(b.tokens)
+	reset = seconds((burst - b.tokens) / l.opts.Rate)
+	return
+}
+
+// sweep drops buckets that have refilled completely, since they hold no state worth keeping.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	burst := float64(l.opts.Burst)
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.opts.Rate >= burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func seconds(value float64) int {
+	return int(math.Ceil(value))
+}
+
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
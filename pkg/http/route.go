@@ -0,0 +1,35 @@
+package http_adapter
+
+import (
+	"context"
+	"net/http"
+)
+
+type routeKey struct{}
+
+type route struct{ pattern string }
+
+// WithRouteCapture lets an outer middleware read the matched route template
+// through RoutePattern once the inner handler has been served.
+func WithRouteCapture(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(routeKey{}).(*route); ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, &route{}))
+}
+
+func RoutePattern(ctx context.Context) (result string) {
+	rt, ok := ctx.Value(routeKey{}).(*route)
+	if !ok {
+		return ""
+	}
+	return rt.pattern
+}
+
+func routed(pattern string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = WithRouteCapture(r)
+		r.Context().Value(routeKey{}).(*route).pattern = pattern
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,88 @@
+package http_adapter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	http_adapter "github.com/rochaeduardo997/irede_golang_dev/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func forEachAdapter(t *testing.T, test func(t *testing.T, newAdapter http_adapter.Factory)) {
+	for name, newAdapter := range http_adapter.Adapters {
+		t.Run(name, func(t *testing.T) { test(t, newAdapter) })
+	}
+}
+
+func TestParam(t *testing.T) { forEachAdapter(t, testParam) }
+
+func testParam(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.AddRoute("get", "/movies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("id=" + httpAdapter.Param(r, "id")))
+	})
+	httpAdapter.AddRoute("get", "/movies/all/{page}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("page=" + httpAdapter.Param(r, "page")))
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/movies/abc", nil))
+	assert.Equal(t, "id=abc", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/movies/all/2", nil))
+	assert.Equal(t, "page=2", rec.Body.String())
+}
+
+func TestMethodNotAllowed(t *testing.T) { forEachAdapter(t, testMethodNotAllowed) }
+
+func testMethodNotAllowed(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.AddRoute("post", "/movies", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/movies", nil))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/movies", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestGroup(t *testing.T) { forEachAdapter(t, testGroup) }
+
+func testGroup(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	api := httpAdapter.Group("/api", tagMiddleware("api"))
+	movies := api.Group("/movies", tagMiddleware("movies"))
+	movies.AddRoute("get", "/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(movies.Param(r, "id")))
+	}, tagMiddleware("route"))
+	httpAdapter.AddRoute("get", "/public", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/movies/abc", nil))
+	assert.Equal(t, "abc", rec.Body.String())
+	assert.Equal(t, []string{"api", "movies", "route"}, rec.Header().Values("X-Order"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/public", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Values("X-Order"))
+}
+
+func TestPrefixRoute(t *testing.T) { forEachAdapter(t, testPrefixRoute) }
+
+func testPrefixRoute(t *testing.T, newAdapter http_adapter.Factory) {
+	httpAdapter, handler := newAdapter()
+	httpAdapter.AddPrefixRoute("/swagger", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+	assert.Equal(t, "/swagger/index.html", rec.Body.String())
+}
@@ -0,0 +1,58 @@
+package http_adapter
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+)
+
+type ServeMux struct {
+	Mux         *http.ServeMux
+	middlewares []Middleware
+	handler     http.Handler
+}
+
+func NewServeMux() (result IHTTP, handler http.Handler) {
+	sm := &ServeMux{}
+	sm.Mux = http.NewServeMux()
+	sm.handler = sm.Mux
+	result = sm
+	return result, sm
+}
+
+func (sm *ServeMux) AddRoute(method, url string, callback func(w http.ResponseWriter, r *http.Request), middlewares ...Middleware) {
+	pattern := fmt.Sprintf("%s %s", strings.ToUpper(method), url)
+	sm.Mux.Handle(pattern, routed(url, Chain(http.HandlerFunc(callback), middlewares...)))
+}
+
+func (sm *ServeMux) AddPrefixRoute(prefix string, handler http.Handler, middlewares ...Middleware) {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	sm.Mux.Handle(prefix, routed(prefix+"*", Chain(handler, middlewares...)))
+}
+
+func (sm *ServeMux) Group(prefix string, middlewares ...Middleware) IRouter {
+	return NewRouteGroup(sm, prefix, middlewares...)
+}
+
+func (sm *ServeMux) Param(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
+
+func (sm *ServeMux) Use(middlewares ...Middleware) {
+	sm.middlewares = append(sm.middlewares, middlewares...)
+	sm.handler = Chain(sm.Mux, sm.middlewares...)
+}
+
+func (sm *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sm.handler.ServeHTTP(w, r)
+}
+
+func (sm *ServeMux) Listen() {
+	PORT := os.Getenv("API_PORT")
+	slog.Info("server running", "url", fmt.Sprintf("http://localhost:%s", PORT))
+	http.ListenAndServe(fmt.Sprintf(":%s", PORT), sm)
+}
@@ -0,0 +1,132 @@
+package tabular
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Writer streams rows with a fixed set of columns; nothing is held in memory beyond the current row.
+type Writer interface {
+	Row(values ...any) error
+	Close() error
+}
+
+func NewWriter(w io.Writer, format string, columns []string) (result Writer, err error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w, columns: columns}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	}
+	return nil, errors.New("format must be csv, ndjson or xlsx")
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+// flushEvery bounds how many rows sit in the csv buffer before reaching the client.
+const flushEvery = 100
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	return cw, cw.w.Write(columns)
+}
+
+func (cw *csvWriter) Row(values ...any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		if value != nil {
+			record[i] = text(value)
+		}
+	}
+	if err := cw.w.Write(record); err != nil {
+		return err
+	}
+	cw.rows++
+	if cw.rows%flushEvery == 0 {
+		cw.w.Flush()
+	}
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// text renders a CSV cell, quoting strings that a spreadsheet opening the file would otherwise evaluate as a formula.
+func text(value any) string {
+	s := fmt.Sprint(value)
+	if _, ok := value.(string); ok && s != "" && strings.ContainsRune(formulaStart, rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// Unquote drops the quote text puts before formula-like cells, so a CSV export imports back unchanged.
+func Unquote(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune(formulaStart, rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
+
+const formulaStart = "=+-@"
+
+type ndjsonWriter struct {
+	w       io.Writer
+	columns []string
+}
+
+// Row writes one object per line, keeping keys in column order.
+func (nw *ndjsonWriter) Row(values ...any) error {
+	line := []byte{'{'}
+	for i, column := range nw.columns {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		key, _ := json.Marshal(column)
+		var value any
+		if i < len(values) {
+			value = values[i]
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		line = append(append(append(line, key...), ':'), encoded...)
+	}
+	line = append(line, '}', '\n')
+	_, err := nw.w.Write(line)
+	return err
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package tabular_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/rochaeduardo997/irede_golang_dev/pkg/tabular"
+	"github.com/stretchr/testify/assert"
+)
+
+var columns = []string{"id", "name", "durationInSeconds"}
+
+func write(t *testing.T, format string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	w, err := tabular.NewWriter(buf, format, columns)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Row("1", "matrix, the", uint16(5760)))
+	assert.Nil(t, w.Row("2", "<alien>", nil))
+	assert.Nil(t, w.Close())
+	return buf
+}
+
+func sheet(t *testing.T, buf *bytes.Buffer) (result []byte) {
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	for _, f := range archive.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			result, _ = io.ReadAll(r)
+		}
+	}
+	return
+}
+
+func TestCSV(t *testing.T) {
+	assert.Equal(t, "id,name,durationInSeconds\n1,\"matrix, the\",5760\n2,<alien>,\n", write(t, tabular.FormatCSV).String())
+}
+
+func TestNDJSON(t *testing.T) {
+	expected := `{"id":"1","name":"matrix, the","durationInSeconds":5760}` + "\n" +
+		`{"id":"2","name":"\u003calien\u003e","durationInSeconds":null}` + "\n"
+	assert.Equal(t, expected, write(t, tabular.FormatNDJSON).String())
+}
+
+func TestXLSX(t *testing.T) {
+	sheet := sheet(t, write(t, tabular.FormatXLSX))
+	parsed := struct {
+		Rows []struct {
+			Cells []struct {
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}{}
+	assert.Nil(t, xml.Unmarshal(sheet, &parsed))
+	assert.Len(t, parsed.Rows, 3)
+	assert.Equal(t, "durationInSeconds", parsed.Rows[0].Cells[2].Inline)
+	assert.Equal(t, "5760", parsed.Rows[1].Cells[2].Value)
+	assert.Equal(t, "<alien>", parsed.Rows[2].Cells[1].Inline)
+}
+
+func TestFormulasAreQuotedInCSVOnly(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := tabular.NewWriter(buf, tabular.FormatCSV, columns)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Row("=1+1", "@SUM(A1)", -1))
+	assert.Nil(t, w.Row("+1", "-1", "a=b"))
+	assert.Nil(t, w.Close())
+	assert.Equal(t, "id,name,durationInSeconds\n'=1+1,'@SUM(A1),-1\n'+1,'-1,a=b\n", buf.String())
+
+	buf = &bytes.Buffer{}
+	w, err = tabular.NewWriter(buf, tabular.FormatXLSX, columns)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Row("=1+1", "@SUM(A1)", -1))
+	assert.Nil(t, w.Close())
+	sheet := string(sheet(t, buf))
+	assert.Contains(t, sheet, `<t xml:space="preserve">=1+1</t>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">@SUM(A1)</t>`)
+	assert.Contains(t, sheet, `<v>-1</v>`)
+	assert.NotContains(t, sheet, `&#39;`)
+}
+
+func TestUnquote(t *testing.T) {
+	assert.Equal(t, "=1+1", tabular.Unquote("'=1+1"))
+	assert.Equal(t, "-1", tabular.Unquote("'-1"))
+	assert.Equal(t, "'quoted'", tabular.Unquote("'quoted'"))
+	assert.Equal(t, "'", tabular.Unquote("'"))
+}
+
+func TestFailUnknownFormat(t *testing.T) {
+	_, err := tabular.NewWriter(&bytes.Buffer{}, "pdf", columns)
+	assert.EqualError(t, err, "format must be csv, ndjson or xlsx")
+}
@@ -0,0 +1,108 @@
+package tabular
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The smallest package Excel and LibreOffice accept: one sheet, inline strings and no styles, so rows stream straight into the zip.
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	rows  int
+}
+
+func newXLSXWriter(w io.Writer, columns []string) (*xlsxWriter, error) {
+	xw := &xlsxWriter{zip: zip.NewWriter(w)}
+	for _, part := range xlsxParts {
+		f, err := xw.zip.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+	sheet, err := xw.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw.sheet = sheet
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]any, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	return xw, xw.Row(header...)
+}
+
+func (xw *xlsxWriter) Row(values ...any) (err error) {
+	xw.rows++
+	if _, err = fmt.Fprintf(xw.sheet, `<row r="%d">`, xw.rows); err != nil {
+		return err
+	}
+	for _, value := range values {
+		if err = xw.cell(value); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(xw.sheet, `</row>`)
+	return
+}
+
+func (xw *xlsxWriter) cell(value any) (err error) {
+	var number string
+	switch v := value.(type) {
+	case nil:
+		_, err = io.WriteString(xw.sheet, `<c/>`)
+		return
+	case int:
+		number = strconv.Itoa(v)
+	case int64:
+		number = strconv.FormatInt(v, 10)
+	case uint16:
+		number = strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		number = strconv.FormatUint(uint64(v), 10)
+	case float64:
+		number = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	if number != "" {
+		_, err = fmt.Fprintf(xw.sheet, `<c><v>%s</v></c>`, number)
+		return
+	}
+	// Inline strings are never evaluated, so unlike CSV they keep formula-like text as it is.
+	if _, err = io.WriteString(xw.sheet, `<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+		return
+	}
+	if err = xml.EscapeText(xw.sheet, []byte(fmt.Sprint(value))); err != nil {
+		return
+	}
+	_, err = io.WriteString(xw.sheet, `</t></is></c>`)
+	return
+}
+
+func (xw *xlsxWriter) Close() error {
+	if _, err := io.WriteString(xw.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return xw.zip.Close()
+}
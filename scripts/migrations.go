@@ -0,0 +1,9 @@
+package scripts
+
+import "embed"
+
+// Migrations are applied in name order by `cinemactl migrate` and by the MySQL container on first start;
+// each file ends by recording its own version in schema_migrations so both paths agree on what ran.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -1,4 +1,10 @@
-CREATE TABLE movies (
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version VARCHAR(100),
+  applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(version)
+);
+
+CREATE TABLE IF NOT EXISTS movies (
   id VARCHAR(50),
   name VARCHAR(50) NOT NULL,
   director VARCHAR(50) NOT NULL,
@@ -6,17 +12,19 @@ CREATE TABLE movies (
   PRIMARY KEY(id)
 );
 
-CREATE TABLE rooms (
+CREATE TABLE IF NOT EXISTS rooms (
   id VARCHAR(50),
   number INTEGER NOT NULL,
   description VARCHAR(50) NOT NULL,
   PRIMARY KEY(id)
 );
 
-CREATE TABLE room_movies (
+CREATE TABLE IF NOT EXISTS room_movies (
   fk_room_id VARCHAR(50) NOT NULL,
   FOREIGN KEY (fk_room_id) REFERENCES rooms(id),
   fk_movie_id VARCHAR(50) NOT NULL,
   FOREIGN KEY (fk_movie_id) REFERENCES movies(id),
   PRIMARY KEY(fk_room_id, fk_movie_id)
-);
\ No newline at end of file
+);
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0001_initial_schema');
@@ -0,0 +1,11 @@
+CREATE TABLE users (
+  id VARCHAR(50),
+  username VARCHAR(50) NOT NULL,
+  password_hash VARCHAR(100) NOT NULL,
+  roles VARCHAR(255) NOT NULL,
+  disabled BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY(id),
+  UNIQUE KEY uq_users_username (username)
+);
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0002_users');
@@ -0,0 +1,15 @@
+CREATE TABLE api_keys (
+  id VARCHAR(50),
+  name VARCHAR(50) NOT NULL,
+  prefix VARCHAR(20) NOT NULL,
+  key_hash CHAR(64) NOT NULL,
+  permissions VARCHAR(255) NOT NULL,
+  room_ids TEXT NOT NULL,
+  created_at DATETIME NOT NULL,
+  last_used_at DATETIME NULL,
+  revoked_at DATETIME NULL,
+  PRIMARY KEY(id),
+  UNIQUE KEY uq_api_keys_prefix (prefix)
+);
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0003_api_keys');
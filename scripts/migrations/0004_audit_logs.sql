@@ -0,0 +1,14 @@
+CREATE TABLE audit_logs (
+  id VARCHAR(50),
+  actor VARCHAR(100) NOT NULL,
+  action VARCHAR(10) NOT NULL,
+  entity VARCHAR(50) NOT NULL,
+  entity_id VARCHAR(50) NOT NULL,
+  changes JSON NOT NULL,
+  created_at DATETIME(6) NOT NULL,
+  PRIMARY KEY(id),
+  KEY idx_audit_logs_entity (entity, created_at),
+  KEY idx_audit_logs_actor (actor, created_at)
+);
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0004_audit_logs');
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS cinemas (
+  id VARCHAR(50),
+  name VARCHAR(50) NOT NULL,
+  city VARCHAR(50) NOT NULL DEFAULT '',
+  PRIMARY KEY(id)
+);
+
+ALTER TABLE rooms ADD COLUMN fk_cinema_id VARCHAR(50) NULL, ADD FOREIGN KEY (fk_cinema_id) REFERENCES cinemas(id);
+
+-- Rooms created before cinemas existed move to a default cinema, which is only created when there are such rooms.
+INSERT IGNORE INTO cinemas(id, name) SELECT DISTINCT 'default-cinema', 'Default cinema' FROM rooms WHERE fk_cinema_id IS NULL;
+
+UPDATE rooms SET fk_cinema_id = 'default-cinema' WHERE fk_cinema_id IS NULL;
+
+ALTER TABLE rooms MODIFY COLUMN fk_cinema_id VARCHAR(50) NOT NULL;
+
+ALTER TABLE users ADD COLUMN cinema_id VARCHAR(50) NULL, ADD FOREIGN KEY (cinema_id) REFERENCES cinemas(id);
+
+ALTER TABLE api_keys ADD COLUMN cinema_id VARCHAR(50) NULL, ADD FOREIGN KEY (cinema_id) REFERENCES cinemas(id);
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0005_cinemas');
@@ -0,0 +1,3 @@
+ALTER TABLE movies ADD COLUMN year SMALLINT UNSIGNED NOT NULL DEFAULT 0;
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0006_movie_year');
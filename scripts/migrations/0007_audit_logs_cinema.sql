@@ -0,0 +1,8 @@
+ALTER TABLE audit_logs ADD COLUMN cinema_id VARCHAR(50) NULL;
+
+-- Room entries belong to the cinema of their room; earlier movie entries stay visible to unscoped callers only.
+UPDATE audit_logs SET cinema_id = (SELECT fk_cinema_id FROM rooms WHERE rooms.id = audit_logs.entity_id) WHERE entity = 'room';
+
+CREATE INDEX idx_audit_logs_cinema ON audit_logs (cinema_id, created_at);
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0007_audit_logs_cinema');
@@ -0,0 +1,6 @@
+-- The baseline schema allowed rooms sharing a number; stop before the index with a message rather than a bare duplicate-key error.
+SELECT 'rooms share a number within a cinema: renumber them and run the migrations again' FROM rooms GROUP BY fk_cinema_id, number HAVING COUNT(*) > 1 LIMIT 1;
+
+CREATE UNIQUE INDEX uq_rooms_cinema_number ON rooms (fk_cinema_id, number);
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0008_rooms_cinema_number_unique');
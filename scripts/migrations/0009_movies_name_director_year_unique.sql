@@ -0,0 +1,7 @@
+-- Movies of the baseline schema had no year, so the same name and director may appear twice; setting their years apart
+-- or removing the copies lets the migration run again.
+SELECT 'movies share a name, director and year: set their years apart or remove the copies and run the migrations again' FROM movies GROUP BY name, director, year HAVING COUNT(*) > 1 LIMIT 1;
+
+CREATE UNIQUE INDEX uq_movies_name_director_year ON movies (name, director, year);
+
+INSERT IGNORE INTO schema_migrations(version) VALUES ('0009_movies_name_director_year_unique');